@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// The go-gitlab types model API v3; API v4 renamed a few fields (builds are
+// now jobs, visibility levels are now strings, and so on). The functions in
+// this file wrap the calls whose payloads changed: on v3 they go through the
+// go-gitlab client as they are, while on v4 the v3 fields are mapped onto
+// their v4 counterparts on the way out, and back on the way in, so that the
+// resources can keep dealing with the go-gitlab types only.
+
+// projectV4 is a project as returned by API v4.
+type projectV4 struct {
+	gitlab.Project
+	JobsEnabled                      bool   `json:"jobs_enabled"`
+	PublicJobs                       bool   `json:"public_jobs"`
+	Visibility                       string `json:"visibility"`
+	OnlyAllowMergeIfPipelineSucceeds bool   `json:"only_allow_merge_if_pipeline_succeeds"`
+}
+
+func (p *projectV4) project() *gitlab.Project {
+	project := p.Project
+	project.BuildsEnabled = p.JobsEnabled
+	project.PublicBuilds = p.PublicJobs
+	project.OnlyAllowMergeIfBuildSucceeds = p.OnlyAllowMergeIfPipelineSucceeds
+	if level := stringToVisibilityLevel(p.Visibility); level != nil {
+		project.VisibilityLevel = *level
+	}
+	return &project
+}
+
+// projectOptionsV4 holds the v4 fields shared by the create and edit project
+// options.
+type projectOptionsV4 struct {
+	JobsEnabled                      *bool   `url:"jobs_enabled,omitempty" json:"jobs_enabled,omitempty"`
+	PublicJobs                       *bool   `url:"public_jobs,omitempty" json:"public_jobs,omitempty"`
+	Visibility                       *string `url:"visibility,omitempty" json:"visibility,omitempty"`
+	OnlyAllowMergeIfPipelineSucceeds *bool   `url:"only_allow_merge_if_pipeline_succeeds,omitempty" json:"only_allow_merge_if_pipeline_succeeds,omitempty"`
+}
+
+type createProjectOptionsV4 struct {
+	gitlab.CreateProjectOptions
+	projectOptionsV4
+}
+
+type editProjectOptionsV4 struct {
+	gitlab.EditProjectOptions
+	projectOptionsV4
+}
+
+// visibilityV4 converts a v3 visibility level into its v4 string, if set.
+func visibilityV4(level *gitlab.VisibilityLevelValue) *string {
+	if level == nil {
+		return nil
+	}
+	return visibilityLevelToString(*level)
+}
+
+func getProject(client *Client, pid string, options ...gitlab.OptionFunc) (*gitlab.Project, *gitlab.Response, error) {
+	if !client.isV4() {
+		return client.Projects.GetProject(pid, options...)
+	}
+
+	project := &projectV4{}
+	response, err := doRequest(client, "GET", fmt.Sprintf("projects/%s", pathEscape(pid)), nil, project, options...)
+	if err != nil {
+		return nil, response, err
+	}
+	return project.project(), response, nil
+}
+
+func createProject(client *Client, opt *gitlab.CreateProjectOptions, options ...gitlab.OptionFunc) (*gitlab.Project, error) {
+	if !client.isV4() {
+		project, _, err := client.Projects.CreateProject(opt, options...)
+		return project, err
+	}
+
+	v4 := &createProjectOptionsV4{
+		CreateProjectOptions: *opt,
+		projectOptionsV4: projectOptionsV4{
+			JobsEnabled:                      opt.BuildsEnabled,
+			PublicJobs:                       opt.PublicBuilds,
+			Visibility:                       visibilityV4(opt.VisibilityLevel),
+			OnlyAllowMergeIfPipelineSucceeds: opt.OnlyAllowMergeIfBuildSucceeds,
+		},
+	}
+	v4.BuildsEnabled = nil
+	v4.PublicBuilds = nil
+	v4.VisibilityLevel = nil
+	v4.OnlyAllowMergeIfBuildSucceeds = nil
+
+	project := &projectV4{}
+	if _, err := doRequest(client, "POST", "projects", v4, project, options...); err != nil {
+		return nil, err
+	}
+	return project.project(), nil
+}
+
+func editProject(client *Client, pid string, opt *gitlab.EditProjectOptions, options ...gitlab.OptionFunc) error {
+	if !client.isV4() {
+		_, _, err := client.Projects.EditProject(pid, opt, options...)
+		return err
+	}
+
+	v4 := &editProjectOptionsV4{
+		EditProjectOptions: *opt,
+		projectOptionsV4: projectOptionsV4{
+			JobsEnabled:                      opt.BuildsEnabled,
+			PublicJobs:                       opt.PublicBuilds,
+			Visibility:                       visibilityV4(opt.VisibilityLevel),
+			OnlyAllowMergeIfPipelineSucceeds: opt.OnlyAllowMergeIfBuildSucceeds,
+		},
+	}
+	v4.BuildsEnabled = nil
+	v4.PublicBuilds = nil
+	v4.VisibilityLevel = nil
+	v4.OnlyAllowMergeIfBuildSucceeds = nil
+
+	_, err := doRequest(client, "PUT", fmt.Sprintf("projects/%s", pathEscape(pid)), v4, nil, options...)
+	return err
+}
+
+// groupV4 is a group as returned by API v4.
+type groupV4 struct {
+	gitlab.Group
+	Visibility string `json:"visibility"`
+}
+
+func (g *groupV4) group() *gitlab.Group {
+	group := g.Group
+	if level := stringToVisibilityLevel(g.Visibility); level != nil {
+		group.VisibilityLevel = *level
+	}
+	return &group
+}
+
+type createGroupOptionsV4 struct {
+	gitlab.CreateGroupOptions
+	Visibility *string `url:"visibility,omitempty" json:"visibility,omitempty"`
+}
+
+type updateGroupOptionsV4 struct {
+	gitlab.UpdateGroupOptions
+	Visibility *string `url:"visibility,omitempty" json:"visibility,omitempty"`
+}
+
+func getGroup(client *Client, gid string, options ...gitlab.OptionFunc) (*gitlab.Group, *gitlab.Response, error) {
+	if !client.isV4() {
+		return client.Groups.GetGroup(gid, options...)
+	}
+
+	group := &groupV4{}
+	response, err := doRequest(client, "GET", fmt.Sprintf("groups/%s", pathEscape(gid)), nil, group, options...)
+	if err != nil {
+		return nil, response, err
+	}
+	return group.group(), response, nil
+}
+
+func createGroup(client *Client, opt *gitlab.CreateGroupOptions, options ...gitlab.OptionFunc) (*gitlab.Group, error) {
+	if !client.isV4() {
+		group, _, err := client.Groups.CreateGroup(opt, options...)
+		return group, err
+	}
+
+	v4 := &createGroupOptionsV4{
+		CreateGroupOptions: *opt,
+		Visibility:         visibilityV4(opt.VisibilityLevel),
+	}
+	v4.VisibilityLevel = nil
+
+	group := &groupV4{}
+	if _, err := doRequest(client, "POST", "groups", v4, group, options...); err != nil {
+		return nil, err
+	}
+	return group.group(), nil
+}
+
+func updateGroup(client *Client, gid string, opt *gitlab.UpdateGroupOptions, options ...gitlab.OptionFunc) error {
+	if !client.isV4() {
+		_, _, err := client.Groups.UpdateGroup(gid, opt, options...)
+		return err
+	}
+
+	v4 := &updateGroupOptionsV4{
+		UpdateGroupOptions: *opt,
+		Visibility:         visibilityV4(opt.VisibilityLevel),
+	}
+	v4.VisibilityLevel = nil
+
+	_, err := doRequest(client, "PUT", fmt.Sprintf("groups/%s", pathEscape(gid)), v4, nil, options...)
+	return err
+}
+
+// projectHookV4 is a project hook as returned by API v4.
+type projectHookV4 struct {
+	gitlab.ProjectHook
+	JobEvents bool `json:"job_events"`
+}
+
+func (h *projectHookV4) hook() *gitlab.ProjectHook {
+	hook := h.ProjectHook
+	hook.BuildEvents = h.JobEvents
+	return &hook
+}
+
+type addProjectHookOptionsV4 struct {
+	gitlab.AddProjectHookOptions
+	JobEvents *bool `url:"job_events,omitempty" json:"job_events,omitempty"`
+}
+
+type editProjectHookOptionsV4 struct {
+	gitlab.EditProjectHookOptions
+	JobEvents *bool `url:"job_events,omitempty" json:"job_events,omitempty"`
+}
+
+func getProjectHook(client *Client, pid string, hook int, options ...gitlab.OptionFunc) (*gitlab.ProjectHook, *gitlab.Response, error) {
+	if !client.isV4() {
+		return client.Projects.GetProjectHook(pid, hook, options...)
+	}
+
+	v4 := &projectHookV4{}
+	response, err := doRequest(client, "GET", fmt.Sprintf("projects/%s/hooks/%d", pathEscape(pid), hook), nil, v4, options...)
+	if err != nil {
+		return nil, response, err
+	}
+	return v4.hook(), response, nil
+}
+
+func addProjectHook(client *Client, pid string, opt *gitlab.AddProjectHookOptions, options ...gitlab.OptionFunc) (*gitlab.ProjectHook, error) {
+	if !client.isV4() {
+		hook, _, err := client.Projects.AddProjectHook(pid, opt, options...)
+		return hook, err
+	}
+
+	v4 := &addProjectHookOptionsV4{
+		AddProjectHookOptions: *opt,
+		JobEvents:             opt.BuildEvents,
+	}
+	v4.BuildEvents = nil
+
+	hook := &projectHookV4{}
+	if _, err := doRequest(client, "POST", fmt.Sprintf("projects/%s/hooks", pathEscape(pid)), v4, hook, options...); err != nil {
+		return nil, err
+	}
+	return hook.hook(), nil
+}
+
+func editProjectHook(client *Client, pid string, hook int, opt *gitlab.EditProjectHookOptions, options ...gitlab.OptionFunc) error {
+	if !client.isV4() {
+		_, _, err := client.Projects.EditProjectHook(pid, hook, opt, options...)
+		return err
+	}
+
+	v4 := &editProjectHookOptionsV4{
+		EditProjectHookOptions: *opt,
+		JobEvents:              opt.BuildEvents,
+	}
+	v4.BuildEvents = nil
+
+	_, err := doRequest(client, "PUT", fmt.Sprintf("projects/%s/hooks/%d", pathEscape(pid), hook), v4, nil, options...)
+	return err
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// the supported ways of authenticating against gitlab
+const (
+	authPrivateToken = "private_token"
+	authOAuthToken   = "oauth_token"
+	authJobToken     = "job_token"
+	authPassword     = "password"
+)
+
+var authTypes = []string{authPrivateToken, authOAuthToken, authJobToken, authPassword}
+
+// authType returns the configured authentication type; if none is given, it
+// is inferred from the credentials that have been provided.
+func (c *Config) authType() (string, error) {
+	if c.AuthType != "" {
+		return c.AuthType, nil
+	}
+
+	switch {
+	case c.Token != "":
+		return authPrivateToken, nil
+	case c.OAuthToken != "":
+		return authOAuthToken, nil
+	case c.Username != "":
+		return authPassword, nil
+	case c.JobToken != "":
+		// last, as within CI jobs it is always there in the environment
+		return authJobToken, nil
+	}
+	return "", fmt.Errorf("No credentials provided: one of token, oauth_token, username and password or job_token must be set")
+}
+
+// gitlabClient returns a *gitlab.Client that authenticates as configured;
+// the base URL is set later on, once the API version is known.
+func (c *Config) gitlabClient(httpClient *http.Client, root string) (*gitlab.Client, error) {
+	authType, err := c.authType()
+	if err != nil {
+		return nil, err
+	}
+
+	switch authType {
+	case authPrivateToken:
+		if c.Token == "" {
+			return nil, fmt.Errorf("token must be set to authenticate with a private token")
+		}
+		return gitlab.NewClient(httpClient, c.Token), nil
+
+	case authOAuthToken:
+		if c.OAuthToken == "" {
+			return nil, fmt.Errorf("oauth_token must be set to authenticate with an OAuth2 token")
+		}
+		return gitlab.NewOAuthClient(httpClient, c.OAuthToken), nil
+
+	case authJobToken:
+		if c.JobToken == "" {
+			return nil, fmt.Errorf("job_token must be set to authenticate with a CI job token")
+		}
+		httpClient.Transport = &jobTokenTransport{
+			transport: httpClient.Transport,
+			token:     c.JobToken,
+		}
+		return gitlab.NewClient(httpClient, ""), nil
+
+	case authPassword:
+		if c.Username == "" || c.Password == "" {
+			return nil, fmt.Errorf("username and password must be set to authenticate with a password")
+		}
+		token, err := passwordGrant(httpClient, root, c.Username, c.Password)
+		if err != nil {
+			return nil, err
+		}
+		return gitlab.NewOAuthClient(httpClient, token), nil
+	}
+	return nil, fmt.Errorf("Unsupported authentication type: %q", authType)
+}
+
+// probe returns the request used to test the credentials: normally the
+// authenticated user is retrieved, but CI job tokens cannot access the user
+// API, so the job the token belongs to is retrieved instead.
+func (c *Config) probe(client *gitlab.Client) func() (*gitlab.Response, error) {
+	if authType, _ := c.authType(); authType == authJobToken {
+		return func() (*gitlab.Response, error) {
+			request, err := client.NewRequest("GET", "job", nil, nil)
+			if err != nil {
+				return nil, err
+			}
+			return client.Do(request, nil)
+		}
+	}
+	return func() (*gitlab.Response, error) {
+		_, response, err := client.Users.CurrentUser()
+		return response, err
+	}
+}
+
+// jobTokenTransport authenticates requests with a CI job token, which goes
+// in its own header rather than in the private token one.
+type jobTokenTransport struct {
+	transport http.RoundTripper
+	token     string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *jobTokenTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	authenticated := new(http.Request)
+	*authenticated = *request
+	authenticated.Header = make(http.Header, len(request.Header))
+	for k, v := range request.Header {
+		authenticated.Header[k] = v
+	}
+	authenticated.Header.Del("PRIVATE-TOKEN")
+	authenticated.Header.Set("JOB-TOKEN", t.token)
+	return t.transport.RoundTrip(authenticated)
+}
+
+// sudoTransport makes requests on behalf of another user, unless they are
+// already meant to act as someone else (e.g. because of a resource-level
+// sudo).
+type sudoTransport struct {
+	transport http.RoundTripper
+	sudo      string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *sudoTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Header.Get("SUDO") != "" {
+		return t.transport.RoundTrip(request)
+	}
+	impersonated := new(http.Request)
+	*impersonated = *request
+	impersonated.Header = make(http.Header, len(request.Header))
+	for k, v := range request.Header {
+		impersonated.Header[k] = v
+	}
+	impersonated.Header.Set("SUDO", t.sudo)
+	return t.transport.RoundTrip(impersonated)
+}
+
+// passwordGrant exchanges a username and password for an OAuth2 access token
+// through the resource owner password credentials flow.
+func passwordGrant(httpClient *http.Client, root, username, password string) (string, error) {
+	form := url.Values{
+		"grant_type": {"password"},
+		"username":   {username},
+		"password":   {password},
+	}
+	response, err := httpClient.Post(root+"/oauth/token", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("Error requesting an OAuth2 token for %s: %s", username, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Error requesting an OAuth2 token for %s: %s", username, response.Status)
+	}
+
+	token := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("Error decoding the OAuth2 token for %s: %s", username, err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("No OAuth2 token returned for %s", username)
+	}
+	return token.AccessToken, nil
+}
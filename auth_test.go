@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConfig_authType(t *testing.T) {
+	cases := []struct {
+		Config   Config
+		AuthType string
+		Error    bool
+	}{
+		{
+			Config:   Config{Token: "secret"},
+			AuthType: authPrivateToken,
+		},
+		{
+			Config:   Config{OAuthToken: "secret", JobToken: "secret"},
+			AuthType: authOAuthToken,
+		},
+		{
+			Config:   Config{Username: "root", Password: "secret", JobToken: "secret"},
+			AuthType: authPassword,
+		},
+		{
+			Config:   Config{JobToken: "secret"},
+			AuthType: authJobToken,
+		},
+		{
+			Config:   Config{AuthType: authJobToken, Token: "secret", JobToken: "secret"},
+			AuthType: authJobToken,
+		},
+		{
+			Config: Config{},
+			Error:  true,
+		},
+	}
+	for _, tc := range cases {
+		authType, err := tc.Config.authType()
+		if (err != nil) != tc.Error {
+			t.Fatalf("%+v - got error %v, expected error: %t", tc.Config, err, tc.Error)
+		}
+		if authType != tc.AuthType {
+			t.Fatalf("%+v - got %q expected %q", tc.Config, authType, tc.AuthType)
+		}
+	}
+}
+
+func TestJobTokenTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("JOB-TOKEN") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: &jobTokenTransport{
+			transport: http.DefaultTransport,
+			token:     "secret",
+		},
+	}
+	request, err := http.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	request.Header.Set("PRIVATE-TOKEN", "")
+
+	response, err := client.Do(request)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d expected %d", response.StatusCode, http.StatusOK)
+	}
+	if _, ok := request.Header["Private-Token"]; !ok {
+		t.Fatalf("the original request must not be modified")
+	}
+}
+
+func TestPasswordGrant(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/token" || r.FormValue("grant_type") != "password" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.FormValue("username") != "root" || r.FormValue("password") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"access_token": "token", "token_type": "bearer"}`)
+	}))
+	defer server.Close()
+
+	token, err := passwordGrant(http.DefaultClient, server.URL, "root", "secret")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if token != "token" {
+		t.Fatalf("got token %q expected %q", token, "token")
+	}
+
+	if _, err := passwordGrant(http.DefaultClient, server.URL, "root", "wrong"); err == nil {
+		t.Fatalf("expected an error with the wrong password")
+	}
+}
+
+func TestSudoTransport(t *testing.T) {
+	sudo := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sudo = r.Header.Get("SUDO")
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: &sudoTransport{
+			transport: http.DefaultTransport,
+			sudo:      "admin",
+		},
+	}
+
+	cases := []struct {
+		Header string
+		Sudo   string
+	}{
+		{
+			Header: "",
+			Sudo:   "admin",
+		},
+		{
+			Header: "someone",
+			Sudo:   "someone",
+		},
+	}
+	for _, tc := range cases {
+		request, err := http.NewRequest("GET", server.URL, nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if tc.Header != "" {
+			request.Header.Set("SUDO", tc.Header)
+		}
+		if _, err := client.Do(request); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if sudo != tc.Sudo {
+			t.Fatalf("got sudo %q expected %q", sudo, tc.Sudo)
+		}
+	}
+}
@@ -1,31 +1,192 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
 	"github.com/xanzy/go-gitlab"
 )
 
+const (
+	// the base URL of gitlab.com, used when none is configured
+	defaultBaseURL = "https://gitlab.com"
+
+	apiV3 = "v3"
+	apiV4 = "v4"
+)
+
+// a base URL may or may not include the API path (e.g. "/api/v4"); if it
+// does, it pins the API version
+var apiPath = regexp.MustCompile(`/api/(v[0-9]+)/?$`)
+
 // Config is per-provider, specifies where to connect to gitlab
 type Config struct {
-	Token   string
-	BaseURL string
+	AuthType   string
+	Token      string
+	OAuthToken string
+	JobToken   string
+	Username   string
+	Password   string
+	Sudo       string
+	BaseURL    string
+	APIVersion string
+	CACertFile string
+	ClientCert string
+	ClientKey  string
+	Insecure   bool
+
+	MaxRetries        int
+	RequestsPerSecond int
+}
+
+// Client is the per-provider state handed to resources: it embeds the
+// *gitlab.Client used to interact with the configured gitlab instance and
+// holds whatever must be shared across resources, such as the namespaces
+// cache.
+type Client struct {
+	*gitlab.Client
+
+	apiVersion string
+	namespaces *namespaceCache
 }
 
-// Client returns a *gitlab.Client to interact with the configured gitlab instance
+// Client returns a *Client to interact with the configured gitlab instance
 func (c *Config) Client() (interface{}, error) {
-	client := gitlab.NewClient(nil, c.Token)
-	if c.BaseURL != "" {
-		err := client.SetBaseURL(c.BaseURL)
-		if err != nil {
-			// The BaseURL supplied wasn't valid, bail.
-			return nil, err
+	httpClient, err := c.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	root, version := splitBaseURL(c.BaseURL)
+	if c.APIVersion != "" {
+		if version != "" && version != c.APIVersion {
+			return nil, fmt.Errorf("The base URL %q does not match API version %s", c.BaseURL, c.APIVersion)
 		}
+		version = c.APIVersion
 	}
 
-	// Test the credentials by checking we can get information about the authenticated user.
-	_, _, err := client.Users.CurrentUser()
+	client, err := c.gitlabClient(httpClient, root)
 	if err != nil {
 		return nil, err
 	}
 
-	return client, nil
+	version, err = detectAPIVersion(client, root, version, c.probe(client))
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[INFO] using gitlab API %s at %s", version, root)
+
+	return &Client{
+		Client:     client,
+		apiVersion: version,
+		namespaces: newNamespaceCache(),
+	}, nil
+}
+
+// httpClient returns the *http.Client used to talk to gitlab, configured to
+// trust the given CA bundle (on top of the system ones), to present the given
+// client certificate, and optionally to skip the server certificate checks;
+// transient failures are retried and requests are throttled as configured,
+// and made on behalf of the sudo user, if any.
+func (c *Config) httpClient() (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: c.Insecure,
+	}
+
+	if c.CACertFile != "" {
+		pem, err := ioutil.ReadFile(c.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading CA bundle %s: %s", c.CACertFile, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			log.Printf("[WARN] unable to load the system CA certificates, using only %s: %s", c.CACertFile, err)
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("No valid certificates found in CA bundle %s", c.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		if c.ClientCert == "" || c.ClientKey == "" {
+			return nil, fmt.Errorf("Both client_cert and client_key must be provided")
+		}
+		certificate, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading client certificate %s: %s", c.ClientCert, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	transport := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	}
+
+	var roundTripper http.RoundTripper = newRetryTransport(transport, c.MaxRetries, c.RequestsPerSecond)
+	if c.Sudo != "" {
+		roundTripper = &sudoTransport{
+			transport: roundTripper,
+			sudo:      c.Sudo,
+		}
+	}
+
+	return &http.Client{
+		Transport: roundTripper,
+	}, nil
+}
+
+// splitBaseURL separates the root URL of the gitlab instance from the API
+// version, if the latter is part of the given base URL.
+func splitBaseURL(baseURL string) (string, string) {
+	if baseURL == "" {
+		return defaultBaseURL, ""
+	}
+	if match := apiPath.FindStringSubmatchIndex(baseURL); match != nil {
+		return strings.TrimSuffix(baseURL[:match[0]], "/"), baseURL[match[2]:match[3]]
+	}
+	return strings.TrimSuffix(baseURL, "/"), ""
+}
+
+// detectAPIVersion points the client to the API of the given version; if no
+// version is specified, v4 is tried first, falling back to v3 if the gitlab
+// instance does not serve it. Either way, the credentials are tested through
+// the given probe.
+func detectAPIVersion(client *gitlab.Client, root, version string, probe func() (*gitlab.Response, error)) (string, error) {
+	candidates := []string{apiV4, apiV3}
+	if version != "" {
+		candidates = []string{version}
+	}
+
+	var err error
+	for _, candidate := range candidates {
+		if err = client.SetBaseURL(fmt.Sprintf("%s/api/%s/", root, candidate)); err != nil {
+			// The BaseURL supplied wasn't valid, bail.
+			return "", err
+		}
+
+		var response *gitlab.Response
+		response, err = probe()
+		if err == nil {
+			return candidate, nil
+		}
+		if response == nil || response.StatusCode != 404 {
+			return "", err
+		}
+		log.Printf("[DEBUG] gitlab API %s not available at %s", candidate, root)
+	}
+	return "", err
+}
+
+// isV4 returns whether the gitlab instance is being accessed through API v4.
+func (c *Client) isV4() bool {
+	return c.apiVersion == apiV4
 }
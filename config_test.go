@@ -0,0 +1,195 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConfig_splitBaseURL(t *testing.T) {
+	cases := []struct {
+		BaseURL string
+		Root    string
+		Version string
+	}{
+		{
+			BaseURL: "",
+			Root:    defaultBaseURL,
+			Version: "",
+		},
+		{
+			BaseURL: "http://localhost",
+			Root:    "http://localhost",
+			Version: "",
+		},
+		{
+			BaseURL: "http://localhost/",
+			Root:    "http://localhost",
+			Version: "",
+		},
+		{
+			BaseURL: "http://localhost/api/v3",
+			Root:    "http://localhost",
+			Version: "v3",
+		},
+		{
+			BaseURL: "https://example.com/gitlab/api/v4/",
+			Root:    "https://example.com/gitlab",
+			Version: "v4",
+		},
+	}
+	for _, tc := range cases {
+		root, version := splitBaseURL(tc.BaseURL)
+		if root != tc.Root || version != tc.Version {
+			t.Fatalf("%s - got %q and %q expected %q and %q", tc.BaseURL, root, version, tc.Root, tc.Version)
+		}
+	}
+}
+
+func TestConfig_httpClientCACert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	dir := testTempDir(t)
+	defer os.RemoveAll(dir)
+
+	cacert := filepath.Join(dir, "ca.pem")
+	testWritePEM(t, cacert, "CERTIFICATE", server.Certificate().Raw)
+
+	cases := []struct {
+		Name   string
+		Config Config
+		Error  bool
+	}{
+		{
+			Name:   "untrusted",
+			Config: Config{},
+			Error:  true,
+		},
+		{
+			Name:   "cacert_file",
+			Config: Config{CACertFile: cacert},
+			Error:  false,
+		},
+		{
+			Name:   "insecure",
+			Config: Config{Insecure: true},
+			Error:  false,
+		},
+	}
+	for _, tc := range cases {
+		client, err := tc.Config.httpClient()
+		if err != nil {
+			t.Fatalf("%s - unexpected error: %s", tc.Name, err)
+		}
+		_, err = client.Get(server.URL)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%s - got error %v, expected error: %t", tc.Name, err, tc.Error)
+		}
+	}
+}
+
+func TestConfig_httpClientClientCert(t *testing.T) {
+	dir := testTempDir(t)
+	defer os.RemoveAll(dir)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "terraform"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	clientCert := filepath.Join(dir, "client.pem")
+	clientKey := filepath.Join(dir, "client-key.pem")
+	testWritePEM(t, clientCert, "CERTIFICATE", der)
+	testWritePEM(t, clientKey, "EC PRIVATE KEY", keyDer)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(certificate)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  pool,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	cases := []struct {
+		Name   string
+		Config Config
+		Error  bool
+	}{
+		{
+			Name:   "no client certificate",
+			Config: Config{Insecure: true},
+			Error:  true,
+		},
+		{
+			Name:   "client certificate",
+			Config: Config{Insecure: true, ClientCert: clientCert, ClientKey: clientKey},
+			Error:  false,
+		},
+	}
+	for _, tc := range cases {
+		client, err := tc.Config.httpClient()
+		if err != nil {
+			t.Fatalf("%s - unexpected error: %s", tc.Name, err)
+		}
+		_, err = client.Get(server.URL)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%s - got error %v, expected error: %t", tc.Name, err, tc.Error)
+		}
+	}
+
+	if _, err := (&Config{ClientCert: clientCert}).httpClient(); err == nil {
+		t.Fatalf("expected an error with client_cert and no client_key")
+	}
+}
+
+func testTempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "terraform-provider-gitlabx")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return dir
+}
+
+func testWritePEM(t *testing.T, path, kind string, der []byte) {
+	data := pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der})
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// Deploy keys are SSH keys granting access to the repositories of the
+// projects they are enabled on; a key is added to a project and can then be
+// enabled on others, and deleting it from a project only disables it there,
+// unless no other project uses it. go-gitlab knows neither whether keys can
+// push nor how to enable them, so the requests are performed here.
+
+// deployKey is a deploy key, as enabled on a project.
+type deployKey struct {
+	ID        int    `json:"id"`
+	Title     string `json:"title"`
+	Key       string `json:"key"`
+	CanPush   bool   `json:"can_push"`
+	CreatedAt string `json:"created_at"`
+}
+
+// addDeployKeyOptions represents the available options when adding a deploy
+// key to a project.
+type addDeployKeyOptions struct {
+	Title   *string `url:"title,omitempty" json:"title,omitempty"`
+	Key     *string `url:"key,omitempty" json:"key,omitempty"`
+	CanPush *bool   `url:"can_push,omitempty" json:"can_push,omitempty"`
+}
+
+func getDeployKey(client *Client, pid string, key int, options ...gitlab.OptionFunc) (*deployKey, *gitlab.Response, error) {
+	k := &deployKey{}
+	response, err := doRequest(client, "GET", fmt.Sprintf("projects/%s/deploy_keys/%d", pathEscape(pid), key), nil, k, options...)
+	if err != nil {
+		return nil, response, err
+	}
+	return k, response, nil
+}
+
+func addDeployKey(client *Client, pid string, opt *addDeployKeyOptions, options ...gitlab.OptionFunc) (*deployKey, error) {
+	log.Printf("[DEBUG] add deploy key %s to gitlab project %s", *opt.Title, pid)
+
+	k := &deployKey{}
+	_, err := doRequest(client, "POST", fmt.Sprintf("projects/%s/deploy_keys", pathEscape(pid)), opt, k, options...)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// enableDeployKey enables a deploy key, added to another project, on the
+// given project.
+func enableDeployKey(client *Client, pid string, key int, options ...gitlab.OptionFunc) (*deployKey, error) {
+	log.Printf("[DEBUG] enable deploy key %d on gitlab project %s", key, pid)
+
+	k := &deployKey{}
+	_, err := doRequest(client, "POST", fmt.Sprintf("projects/%s/deploy_keys/%d/enable", pathEscape(pid), key), nil, k, options...)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// deleteDeployKey disables a deploy key on the given project, and deletes it
+// if no other project uses it.
+func deleteDeployKey(client *Client, pid string, key int, options ...gitlab.OptionFunc) error {
+	log.Printf("[DEBUG] delete deploy key %d from gitlab project %s", key, pid)
+
+	_, err := doRequest(client, "DELETE", fmt.Sprintf("projects/%s/deploy_keys/%d", pathEscape(pid), key), nil, nil, options...)
+	return err
+}
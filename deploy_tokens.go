@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// Deploy tokens grant read (and possibly write) access to the repositories,
+// registries and packages of a project, or of all the projects of a group.
+// Project and group tokens are managed through the same API, under either
+// projects/:id/deploy_tokens or groups/:id/deploy_tokens, which is not part
+// of the go-gitlab client; the kind argument is either "projects" or
+// "groups". Tokens cannot be retrieved on their own, nor changed, and the
+// token itself is only returned on creation.
+
+// the scopes deploy tokens can be given
+var deployTokenScopes = []string{
+	"read_repository",
+	"read_registry",
+	"write_registry",
+	"read_package_registry",
+	"write_package_registry",
+}
+
+// deployToken is a deploy token of a project or group.
+type deployToken struct {
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	Username  string   `json:"username"`
+	ExpiresAt string   `json:"expires_at"`
+	Scopes    []string `json:"scopes"`
+	Revoked   bool     `json:"revoked"`
+	Expired   bool     `json:"expired"`
+	Token     string   `json:"token"`
+}
+
+// createDeployTokenOptions represents the available options when creating a
+// deploy token.
+type createDeployTokenOptions struct {
+	Name      *string  `url:"name,omitempty" json:"name,omitempty"`
+	Username  *string  `url:"username,omitempty" json:"username,omitempty"`
+	ExpiresAt *string  `url:"expires_at,omitempty" json:"expires_at,omitempty"`
+	Scopes    []string `url:"scopes,omitempty" json:"scopes,omitempty"`
+}
+
+// getDeployToken returns the deploy token of a project or group with the
+// given ID, or nil if there is none.
+func getDeployToken(client *Client, kind, id string, token int, options ...gitlab.OptionFunc) (*deployToken, *gitlab.Response, error) {
+	if !client.isV4() {
+		return nil, nil, fmt.Errorf("Deploy tokens require gitlab API v4")
+	}
+
+	opt := &gitlab.ListOptions{PerPage: 100, Page: 1}
+	for {
+		page := []*deployToken{}
+		response, err := doRequest(client, "GET", fmt.Sprintf("%s/%s/deploy_tokens", kind, pathEscape(id)), opt, &page, options...)
+		if err != nil {
+			return nil, response, err
+		}
+		for _, t := range page {
+			if t.ID == token {
+				return t, response, nil
+			}
+		}
+		if response.NextPage == 0 {
+			return nil, response, nil
+		}
+		opt.Page = response.NextPage
+	}
+}
+
+func createDeployToken(client *Client, kind, id string, opt *createDeployTokenOptions, options ...gitlab.OptionFunc) (*deployToken, error) {
+	if !client.isV4() {
+		return nil, fmt.Errorf("Deploy tokens require gitlab API v4")
+	}
+
+	log.Printf("[DEBUG] create deploy token %s for gitlab %s %s", *opt.Name, kind, id)
+
+	t := &deployToken{}
+	if _, err := doRequest(client, "POST", fmt.Sprintf("%s/%s/deploy_tokens", kind, pathEscape(id)), opt, t, options...); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func deleteDeployToken(client *Client, kind, id string, token int, options ...gitlab.OptionFunc) error {
+	log.Printf("[DEBUG] delete deploy token %d of gitlab %s %s", token, kind, id)
+
+	_, err := doRequest(client, "DELETE", fmt.Sprintf("%s/%s/deploy_tokens/%d", kind, pathEscape(id), token), nil, nil, options...)
+	return err
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Deploy keys are stored at "deploy_keys/:id", and enabled on projects by a
+// link at "projects/:id/deploy_keys/:key", holding whether they can push
+// there.
+
+func (f *fakeGitlab) deployKeyRoutes() {
+	f.route("GET", "projects/:id/deploy_keys/:key", f.getDeployKey)
+	f.route("POST", "projects/:id/deploy_keys", f.addDeployKey)
+	f.route("POST", "projects/:id/deploy_keys/:key/enable", f.enableDeployKey)
+	f.route("DELETE", "projects/:id/deploy_keys/:key", f.deleteDeployKey)
+}
+
+// deployKey renders the given deploy key as enabled on the given project.
+func (f *fakeGitlab) deployKey(project, key string) (fakeObject, *fakeError) {
+	link, ok := f.objects[fmt.Sprintf("%s/deploy_keys/%s", project, key)]
+	if !ok {
+		return nil, fakeNotFound("Deploy Key")
+	}
+	rendered := fakeObject{"can_push": link["can_push"]}
+	for k, v := range f.objects["deploy_keys/"+key] {
+		rendered[k] = v
+	}
+	return rendered, nil
+}
+
+func (f *fakeGitlab) getDeployKey(params []string, body fakeObject) (int, interface{}) {
+	project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, err
+	}
+	key, err := f.deployKey(project, params[1])
+	if err != nil {
+		return 0, err
+	}
+	return http.StatusOK, key
+}
+
+func (f *fakeGitlab) addDeployKey(params []string, body fakeObject) (int, interface{}) {
+	project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, err
+	}
+	for _, field := range []string{"title", "key"} {
+		if body[field] == nil {
+			return 0, fakeBadRequest(field, "is missing")
+		}
+	}
+	fingerprint, e := sshKeyFingerprint(fmt.Sprintf("%v", body["key"]))
+	if e != nil {
+		return 0, fakeBadRequest("key", "is invalid")
+	}
+	// a key that already exists is enabled on the project rather than added
+	// again
+	id := ""
+	for _, key := range f.list("deploy_keys") {
+		if key["fingerprint"] == fingerprint {
+			id = fmt.Sprintf("%v", key["id"])
+		}
+	}
+	if id == "" {
+		n := f.id()
+		id = strconv.Itoa(n)
+		f.objects["deploy_keys/"+id] = fakeObject{
+			"id":          n,
+			"title":       fmt.Sprintf("%v", body["title"]),
+			"key":         body["key"],
+			"fingerprint": fingerprint,
+			"created_at":  time.Now().UTC().Format(time.RFC3339),
+		}
+	}
+	f.objects[fmt.Sprintf("%s/deploy_keys/%s", project, id)] = fakeObject{"can_push": body["can_push"] == true}
+
+	key, _ := f.deployKey(project, id)
+	return http.StatusCreated, key
+}
+
+func (f *fakeGitlab) enableDeployKey(params []string, body fakeObject) (int, interface{}) {
+	project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := f.objects["deploy_keys/"+params[1]]; !ok {
+		return 0, fakeNotFound("Deploy Key")
+	}
+	link := fmt.Sprintf("%s/deploy_keys/%s", project, params[1])
+	if _, ok := f.objects[link]; !ok {
+		f.objects[link] = fakeObject{"can_push": false}
+	}
+
+	key, _ := f.deployKey(project, params[1])
+	return http.StatusCreated, key
+}
+
+func (f *fakeGitlab) deleteDeployKey(params []string, body fakeObject) (int, interface{}) {
+	project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, err
+	}
+	link := fmt.Sprintf("%s/deploy_keys/%s", project, params[1])
+	if _, ok := f.objects[link]; !ok {
+		return 0, fakeNotFound("Deploy Key")
+	}
+	delete(f.objects, link)
+
+	// the key itself goes away with the last project it is enabled on
+	for _, p := range f.list("projects") {
+		if _, ok := f.objects[fmt.Sprintf("projects/%v/deploy_keys/%s", p["id"], params[1])]; ok {
+			return http.StatusNoContent, nil
+		}
+	}
+	delete(f.objects, "deploy_keys/"+params[1])
+	return http.StatusNoContent, nil
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// The repositories of the fake gitlab are modelled as a set of commits, each
+// with a snapshot of all the files in the repository, and a set of branches
+// pointing to them; commits are stored at "projects/:id/repository/commits/:sha"
+// and branches at "projects/:id/repository/branches/:name", while the
+// snapshots are kept aside, by commit SHA.
+
+// fakeAction is a change to a file in a commit.
+type fakeAction struct {
+	action       string
+	filePath     string
+	previousPath string
+	content      string
+}
+
+func (f *fakeGitlab) repositoryRoutes() {
+	f.route("GET", "projects/:id/repository/files/:file", f.getFile)
+	f.route("POST", "projects/:id/repository/files/:file", func(params []string, body fakeObject) (int, interface{}) {
+		return f.writeFile("create", params, body)
+	})
+	f.route("PUT", "projects/:id/repository/files/:file", func(params []string, body fakeObject) (int, interface{}) {
+		return f.writeFile("update", params, body)
+	})
+	f.route("DELETE", "projects/:id/repository/files/:file", func(params []string, body fakeObject) (int, interface{}) {
+		return f.writeFile("delete", params, body)
+	})
+	f.route("GET", "projects/:id/repository/commits/:sha", f.getCommit)
+	f.route("POST", "projects/:id/repository/commits", f.createCommit)
+	f.route("GET", "projects/:id/repository/branches/:branch", f.getBranch)
+	f.route("POST", "projects/:id/repository/branches", f.createBranch)
+	f.route("DELETE", "projects/:id/repository/branches/:branch", f.deleteBranch)
+}
+
+// branchPath returns the path of the given branch of the project at the
+// given path.
+func (f *fakeGitlab) branchPath(project, branch string) string {
+	return fmt.Sprintf("%s/repository/branches/%s", project, url.PathEscape(branch))
+}
+
+// resolveRef returns the SHA of the commit the given branch name or SHA
+// refers to in the project at the given path.
+func (f *fakeGitlab) resolveRef(project, ref string) (string, bool) {
+	if branch, ok := f.objects[f.branchPath(project, ref)]; ok {
+		return branch["commit"].(fakeObject)["id"].(string), true
+	}
+	if _, ok := f.objects[fmt.Sprintf("%s/repository/commits/%s", project, ref)]; ok {
+		return ref, true
+	}
+	return "", false
+}
+
+// commit applies the given actions to the given branch in a new commit; if the
+// branch does not exist, it is created from start, or from scratch if the
+// repository is still empty.
+func (f *fakeGitlab) commit(project, branch, start string, actions []fakeAction, message, authorName, authorEmail string) (fakeObject, *fakeError) {
+	parent, ok := f.resolveRef(project, branch)
+	if !ok && start != "" {
+		if parent, ok = f.resolveRef(project, start); !ok {
+			return nil, fakeBadRequest("start_branch", fmt.Sprintf("%s does not exist", start))
+		}
+	}
+	if !ok && start == "" && len(f.list(project+"/repository/branches")) > 0 {
+		return nil, &fakeError{http.StatusBadRequest, "You can only create or edit files when you are on a branch"}
+	}
+
+	files := map[string]string{}
+	for path, content := range f.trees[parent] {
+		files[path] = content
+	}
+	for _, action := range actions {
+		_, exists := files[action.filePath]
+		switch action.action {
+		case "create":
+			if exists {
+				return nil, &fakeError{http.StatusBadRequest, "A file with this name already exists"}
+			}
+			files[action.filePath] = action.content
+		case "update":
+			if !exists {
+				return nil, &fakeError{http.StatusBadRequest, "A file with this name doesn't exist"}
+			}
+			files[action.filePath] = action.content
+		case "delete":
+			if !exists {
+				return nil, &fakeError{http.StatusBadRequest, "A file with this name doesn't exist"}
+			}
+			delete(files, action.filePath)
+		case "move":
+			if _, ok := files[action.previousPath]; !ok {
+				return nil, &fakeError{http.StatusBadRequest, "A file with this name doesn't exist"}
+			}
+			if exists {
+				return nil, &fakeError{http.StatusBadRequest, "A file with this name already exists"}
+			}
+			files[action.filePath] = files[action.previousPath]
+			if action.content != "" {
+				files[action.filePath] = action.content
+			}
+			delete(files, action.previousPath)
+		default:
+			return nil, fakeBadRequest("actions", fmt.Sprintf("%s is not a valid action", action.action))
+		}
+	}
+
+	if authorName == "" {
+		user := f.objects[fmt.Sprintf("users/%d", f.user)]
+		authorName = user["name"].(string)
+		authorEmail = fmt.Sprintf("%s@example.com", user["username"])
+	}
+	sha := fmt.Sprintf("%040x", f.id())
+	parents := []string{}
+	if parent != "" {
+		parents = append(parents, parent)
+	}
+	commit := fakeObject{
+		"id":           sha,
+		"short_id":     sha[:8],
+		"title":        strings.SplitN(message, "\n", 2)[0],
+		"message":      message,
+		"author_name":  authorName,
+		"author_email": authorEmail,
+		"parent_ids":   parents,
+		"created_at":   time.Now().UTC().Format(time.RFC3339),
+	}
+	f.objects[fmt.Sprintf("%s/repository/commits/%s", project, sha)] = commit
+	f.trees[sha] = files
+
+	f.setBranch(project, branch, commit)
+	return commit, nil
+}
+
+// setBranch points the given branch to the given commit, creating it if
+// needed; the first branch of a project becomes its default branch.
+func (f *fakeGitlab) setBranch(project, branch string, commit fakeObject) {
+	path := f.branchPath(project, branch)
+	if _, ok := f.objects[path]; !ok {
+		if f.objects[project]["default_branch"] == nil {
+			f.objects[project]["default_branch"] = branch
+		}
+		f.objects[path] = fakeObject{
+			"name":      branch,
+			"merged":    false,
+			"protected": false,
+		}
+	}
+	f.objects[path]["commit"] = fakeObject{
+		"id":       commit["id"],
+		"short_id": commit["short_id"],
+		"title":    commit["title"],
+		"message":  commit["message"],
+	}
+	f.objects[path]["default"] = f.objects[project]["default_branch"] == branch
+}
+
+func (f *fakeGitlab) getFile(params []string, body fakeObject) (int, interface{}) {
+	project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, err
+	}
+	ref, _ := body["ref"].(string)
+	sha, ok := f.resolveRef(project, ref)
+	if !ok {
+		return 0, fakeNotFound("Commit")
+	}
+	content, ok := f.trees[sha][params[1]]
+	if !ok {
+		return 0, fakeNotFound("File")
+	}
+
+	// the last commit is the most recent one that changed the file
+	last := sha
+	for {
+		parents := f.objects[fmt.Sprintf("%s/repository/commits/%s", project, last)]["parent_ids"].([]string)
+		if len(parents) == 0 {
+			break
+		}
+		previous, ok := f.trees[parents[0]][params[1]]
+		if !ok || previous != content {
+			break
+		}
+		last = parents[0]
+	}
+
+	parts := strings.Split(params[1], "/")
+	return http.StatusOK, fakeObject{
+		"file_name":      parts[len(parts)-1],
+		"file_path":      params[1],
+		"size":           len(content),
+		"encoding":       "base64",
+		"content":        base64.StdEncoding.EncodeToString([]byte(content)),
+		"content_sha256": fmt.Sprintf("%x", sha256.Sum256([]byte(content))),
+		"ref":            ref,
+		"commit_id":      sha,
+		"last_commit_id": last,
+	}
+}
+
+func (f *fakeGitlab) writeFile(action string, params []string, body fakeObject) (int, interface{}) {
+	project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, err
+	}
+	for _, field := range []string{"branch", "commit_message"} {
+		if body[field] == nil {
+			return 0, fakeBadRequest(field, "is missing")
+		}
+	}
+	content, e := fakeContent(body)
+	if e != nil {
+		return 0, e
+	}
+
+	authorName, _ := body["author_name"].(string)
+	authorEmail, _ := body["author_email"].(string)
+	start, _ := body["start_branch"].(string)
+	branch := fmt.Sprintf("%v", body["branch"])
+	_, e = f.commit(project, branch, start, []fakeAction{{
+		action:   action,
+		filePath: params[1],
+		content:  content,
+	}}, fmt.Sprintf("%v", body["commit_message"]), authorName, authorEmail)
+	if e != nil {
+		return 0, e
+	}
+
+	if action == "delete" {
+		return http.StatusNoContent, nil
+	}
+	status := http.StatusOK
+	if action == "create" {
+		status = http.StatusCreated
+	}
+	return status, fakeObject{"file_path": params[1], "branch": branch}
+}
+
+func (f *fakeGitlab) getCommit(params []string, body fakeObject) (int, interface{}) {
+	project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, err
+	}
+	sha, ok := f.resolveRef(project, params[1])
+	if !ok {
+		return 0, fakeNotFound("Commit")
+	}
+	return http.StatusOK, f.objects[fmt.Sprintf("%s/repository/commits/%s", project, sha)]
+}
+
+func (f *fakeGitlab) createCommit(params []string, body fakeObject) (int, interface{}) {
+	project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, err
+	}
+	for _, field := range []string{"branch", "commit_message", "actions"} {
+		if body[field] == nil {
+			return 0, fakeBadRequest(field, "is missing")
+		}
+	}
+
+	actions := []fakeAction{}
+	list, _ := body["actions"].([]interface{})
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return 0, fakeBadRequest("actions", "is invalid")
+		}
+		content, e := fakeContent(fakeObject(m))
+		if e != nil {
+			return 0, e
+		}
+		action, _ := m["action"].(string)
+		filePath, _ := m["file_path"].(string)
+		previousPath, _ := m["previous_path"].(string)
+		actions = append(actions, fakeAction{
+			action:       action,
+			filePath:     filePath,
+			previousPath: previousPath,
+			content:      content,
+		})
+	}
+
+	authorName, _ := body["author_name"].(string)
+	authorEmail, _ := body["author_email"].(string)
+	start, _ := body["start_branch"].(string)
+	commit, e := f.commit(project, fmt.Sprintf("%v", body["branch"]), start, actions, fmt.Sprintf("%v", body["commit_message"]), authorName, authorEmail)
+	if e != nil {
+		return 0, e
+	}
+	return http.StatusCreated, commit
+}
+
+func (f *fakeGitlab) getBranch(params []string, body fakeObject) (int, interface{}) {
+	project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, err
+	}
+	branch, ok := f.objects[f.branchPath(project, params[1])]
+	if !ok {
+		return 0, fakeNotFound("Branch")
+	}
+
+	// branches are protected by any protected branch whose name, possibly with
+	// wildcards, matches theirs
+	rendered := fakeObject{}
+	for k, v := range branch {
+		rendered[k] = v
+	}
+	for _, protection := range f.list(project + "/protected_branches") {
+		pattern := "^" + strings.Replace(regexp.QuoteMeta(protection["name"].(string)), `\*`, ".*", -1) + "$"
+		if regexp.MustCompile(pattern).MatchString(params[1]) {
+			rendered["protected"] = true
+		}
+	}
+	return http.StatusOK, rendered
+}
+
+func (f *fakeGitlab) createBranch(params []string, body fakeObject) (int, interface{}) {
+	project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, err
+	}
+	for _, field := range []string{"branch", "ref"} {
+		if body[field] == nil {
+			return 0, fakeBadRequest(field, "is missing")
+		}
+	}
+	name := fmt.Sprintf("%v", body["branch"])
+	if _, ok := f.objects[f.branchPath(project, name)]; ok {
+		return 0, &fakeError{http.StatusBadRequest, "Branch already exists"}
+	}
+	sha, ok := f.resolveRef(project, fmt.Sprintf("%v", body["ref"]))
+	if !ok {
+		return 0, &fakeError{http.StatusBadRequest, "Invalid reference name"}
+	}
+
+	f.setBranch(project, name, f.objects[fmt.Sprintf("%s/repository/commits/%s", project, sha)])
+	return f.getBranch([]string{params[0], name}, nil)
+}
+
+func (f *fakeGitlab) deleteBranch(params []string, body fakeObject) (int, interface{}) {
+	project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, err
+	}
+	path := f.branchPath(project, params[1])
+	if _, ok := f.objects[path]; !ok {
+		return 0, fakeNotFound("Branch")
+	}
+	if f.objects[project]["default_branch"] == params[1] {
+		return 0, &fakeError{http.StatusMethodNotAllowed, "The default branch cannot be deleted"}
+	}
+	delete(f.objects, path)
+	return http.StatusNoContent, nil
+}
+
+// fakeContent returns the content of a file given in a request, decoding it
+// if it is base64-encoded.
+func fakeContent(body fakeObject) (string, *fakeError) {
+	content := fmt.Sprintf("%v", body["content"])
+	if body["content"] == nil {
+		content = ""
+	}
+	if body["encoding"] == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return "", fakeBadRequest("content", "is not valid base64")
+		}
+		content = string(decoded)
+	}
+	return content, nil
+}
+
+// The following helpers are meant to be used by tests.
+
+// file returns the content of a file in the given branch of the project at
+// the given path, and whether it exists.
+func (f *fakeGitlab) file(project, branch, path string) (string, bool) {
+	f.Lock()
+	defer f.Unlock()
+
+	sha, ok := f.resolveRef(project, branch)
+	if !ok {
+		return "", false
+	}
+	content, ok := f.trees[sha][path]
+	return content, ok
+}
+
+// push commits the given changes to the given branch of the project at the
+// given path, as if they were pushed by someone.
+func (f *fakeGitlab) push(project, branch string, actions ...fakeAction) {
+	f.Lock()
+	defer f.Unlock()
+
+	if _, err := f.commit(project, branch, "", actions, "Pushed outside of Terraform", "", ""); err != nil {
+		f.t.Fatalf("fake gitlab: cannot push to %s: %v", project, err.message)
+	}
+}
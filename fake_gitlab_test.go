@@ -0,0 +1,1283 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// fakeGitlabToken is the only private token accepted by the fake gitlab.
+const fakeGitlabToken = "fake-token"
+
+// fakeObject is a gitlab object (a group, a project, a hook...) as stored by
+// the fake gitlab and as serialised in its responses.
+type fakeObject map[string]interface{}
+
+// fakeError is an error response of the fake gitlab.
+type fakeError struct {
+	status  int
+	message interface{}
+}
+
+func fakeNotFound(kind string) *fakeError {
+	return &fakeError{http.StatusNotFound, fmt.Sprintf("404 %s Not Found", kind)}
+}
+
+func fakeBadRequest(field, message string) *fakeError {
+	return &fakeError{http.StatusBadRequest, map[string][]string{field: {message}}}
+}
+
+// fakeHandler serves a request, given the parameters matched in the path and
+// the parameters in the query string and in the body; it returns the status
+// code and either the payload of the response or a *fakeError.
+type fakeHandler func(params []string, body fakeObject) (int, interface{})
+
+type fakeRoute struct {
+	method  string
+	pattern []string
+	handler fakeHandler
+}
+
+// fakeCollection describes the objects nested under a project or group, such
+// as hooks or members, which are all served by the same generic handlers.
+type fakeCollection struct {
+	// the field identifying the objects
+	key string
+	// the request parameter carrying the key on creation; if empty, the key
+	// is generated by the fake gitlab
+	param string
+	// the values of the fields that are not given on creation
+	defaults fakeObject
+	// if set, called on creation and update to validate the object and to
+	// fill in the fields that depend on other objects
+	prepare func(object fakeObject) *fakeError
+	// if set, a field holding a token generated by the fake gitlab, which is
+	// only served on creation
+	secret string
+}
+
+// render returns the given object as served after its creation.
+func (c fakeCollection) render(object fakeObject) fakeObject {
+	if c.secret == "" {
+		return object
+	}
+	rendered := fakeObject{}
+	for k, v := range object {
+		if k != c.secret {
+			rendered[k] = v
+		}
+	}
+	return rendered
+}
+
+// fakeGitlab is an in-memory implementation of the subset of the gitlab API
+// v4 used by the provider, served over HTTP: unit tests can point the provider
+// to it, through testFakeGitlabConfig, and inspect or alter its objects to
+// simulate changes made outside of Terraform.
+//
+// Objects are stored by their API path (e.g. "projects/12/hooks/13", where
+// keys that are not IDs, such as branch names, are path-escaped); groups
+// and projects have the namespace-dependent fields (full_path and the like)
+// computed when served, so that transfers are reflected everywhere.
+type fakeGitlab struct {
+	sync.Mutex
+	t       *testing.T
+	server  *httptest.Server
+	nextID  int
+	objects map[string]fakeObject
+	// the files in each commit, by SHA
+	trees  map[string]map[string]string
+	routes []fakeRoute
+	// the user on behalf of whom the current request is served
+	user int
+	// the requests served so far, as "<method> <path>"
+	requests []string
+}
+
+// newFakeGitlab starts a fake gitlab, with an admin user named "root" (whose
+// ID and namespace ID is 1); it must be closed at the end of the test.
+func newFakeGitlab(t *testing.T) *fakeGitlab {
+	f := &fakeGitlab{
+		t:       t,
+		nextID:  10,
+		objects: map[string]fakeObject{},
+		trees:   map[string]map[string]string{},
+	}
+	f.objects["users/1"] = fakeObject{
+		"id":       1,
+		"username": "root",
+		"name":     "Administrator",
+		"state":    "active",
+		"is_admin": true,
+	}
+
+	f.route("GET", "user", f.getCurrentUser)
+	f.route("GET", "users/:id", f.getUser)
+	f.route("GET", "namespaces", f.listNamespaces)
+
+	f.route("POST", "groups", f.createGroup)
+	f.route("GET", "groups/:id", f.getGroup)
+	f.route("PUT", "groups/:id", f.updateGroup)
+	f.route("DELETE", "groups/:id", f.deleteGroup)
+	f.route("POST", "groups/:id/transfer", f.transferGroup)
+
+	f.route("POST", "projects", f.createProject)
+	f.route("GET", "projects/:id", f.getProject)
+	f.route("PUT", "projects/:id", f.updateProject)
+	f.route("DELETE", "projects/:id", f.deleteProject)
+	f.route("PUT", "projects/:id/transfer", f.transferProject)
+
+	f.collection("projects", "hooks", fakeCollection{
+		key: "id",
+		defaults: fakeObject{
+			"push_events":             true,
+			"issues_events":           false,
+			"merge_requests_events":   false,
+			"tag_push_events":         false,
+			"note_events":             false,
+			"job_events":              false,
+			"pipeline_events":         false,
+			"wiki_page_events":        false,
+			"enable_ssl_verification": true,
+		},
+	})
+
+	f.collection("groups", "members", fakeCollection{
+		key:     "id",
+		param:   "user_id",
+		prepare: f.prepareMember,
+	})
+	f.collection("projects", "members", fakeCollection{
+		key:     "id",
+		param:   "user_id",
+		prepare: f.prepareMember,
+	})
+
+	f.collection("projects", "protected_branches", fakeCollection{
+		key:     "name",
+		param:   "name",
+		prepare: f.prepareProtection("push", "merge", "unprotect"),
+	})
+	f.collection("projects", "protected_tags", fakeCollection{
+		key:     "name",
+		param:   "name",
+		prepare: f.prepareProtection("create"),
+	})
+
+	for _, parent := range []string{"projects", "groups"} {
+		f.collection(parent, "deploy_tokens", fakeCollection{
+			key:      "id",
+			defaults: fakeObject{"revoked": false, "expired": false, "expires_at": nil},
+			prepare:  f.prepareDeployToken,
+			secret:   "token",
+		})
+	}
+
+	f.collection("projects", "pipeline_schedules", fakeCollection{
+		key: "id",
+		defaults: fakeObject{
+			"cron_timezone": "UTC",
+			"active":        true,
+			"variables":     []fakeObject{},
+		},
+		prepare: preparePipelineSchedule,
+	})
+	f.route("POST", "projects/:id/pipeline_schedules/:schedule/variables", f.createPipelineScheduleVariable)
+	f.route("PUT", "projects/:id/pipeline_schedules/:schedule/variables/:key", f.editPipelineScheduleVariable)
+	f.route("DELETE", "projects/:id/pipeline_schedules/:schedule/variables/:key", f.deletePipelineScheduleVariable)
+
+	f.collection("projects", "triggers", fakeCollection{
+		key:     "id",
+		prepare: f.preparePipelineTrigger,
+	})
+
+	f.repositoryRoutes()
+	f.deployKeyRoutes()
+	f.variableRoutes()
+
+	for _, kind := range []string{"projects", "groups"} {
+		kind := kind
+		f.route("POST", kind+"/:id/share", func(params []string, body fakeObject) (int, interface{}) {
+			return f.share(kind, params, body)
+		})
+		f.route("DELETE", kind+"/:id/share/:group", func(params []string, body fakeObject) (int, interface{}) {
+			return f.unshare(kind, params, body)
+		})
+	}
+
+	f.server = httptest.NewServer(f)
+	return f
+}
+
+// Close shuts the fake gitlab down.
+func (f *fakeGitlab) Close() {
+	f.server.Close()
+}
+
+// route registers a handler for the given method and path, relative to the
+// API root; path elements starting with ':' match any value.
+func (f *fakeGitlab) route(method, pattern string, handler fakeHandler) {
+	f.routes = append(f.routes, fakeRoute{
+		method:  method,
+		pattern: strings.Split(pattern, "/"),
+		handler: handler,
+	})
+}
+
+// collection registers the list, get, create, update and delete endpoints of
+// the objects of the given kind nested under projects or groups.
+func (f *fakeGitlab) collection(parent, kind string, c fakeCollection) {
+	base := fmt.Sprintf("%s/:id/%s", parent, kind)
+	item := base + "/:key"
+
+	f.route("GET", base, func(params []string, body fakeObject) (int, interface{}) {
+		path, err := f.resolve(parent, params[0])
+		if err != nil {
+			return 0, err
+		}
+		list := []fakeObject{}
+		for _, object := range f.list(fmt.Sprintf("%s/%s", path, kind)) {
+			list = append(list, c.render(object))
+		}
+		return http.StatusOK, list
+	})
+	f.route("GET", item, func(params []string, body fakeObject) (int, interface{}) {
+		path, err := f.resolve(parent, params[0])
+		if err != nil {
+			return 0, err
+		}
+		object, ok := f.objects[fmt.Sprintf("%s/%s/%s", path, kind, url.PathEscape(params[1]))]
+		if !ok {
+			return 0, fakeNotFound(kind)
+		}
+		return http.StatusOK, c.render(object)
+	})
+	f.route("POST", base, func(params []string, body fakeObject) (int, interface{}) {
+		path, err := f.resolve(parent, params[0])
+		if err != nil {
+			return 0, err
+		}
+		object := fakeObject{}
+		for k, v := range c.defaults {
+			object[k] = v
+		}
+		for k, v := range body {
+			object[k] = v
+		}
+		key := ""
+		if c.param == "" {
+			object[c.key] = f.id()
+			key = fmt.Sprintf("%v", object[c.key])
+		} else {
+			if body[c.param] == nil {
+				return 0, fakeBadRequest(c.param, "is missing")
+			}
+			key = url.PathEscape(fmt.Sprintf("%v", body[c.param]))
+			if _, ok := f.objects[fmt.Sprintf("%s/%s/%s", path, kind, key)]; ok {
+				return 0, &fakeError{http.StatusConflict, fmt.Sprintf("%s already exists", key)}
+			}
+			delete(object, c.param)
+			object[c.key] = body[c.param]
+		}
+		object["created_at"] = time.Now().UTC().Format(time.RFC3339)
+		if c.secret != "" {
+			object[c.secret] = fmt.Sprintf("fake-%s-%d", strings.Replace(c.secret, "_", "-", -1), f.id())
+		}
+		if c.prepare != nil {
+			if err := c.prepare(object); err != nil {
+				return 0, err
+			}
+		}
+		f.objects[fmt.Sprintf("%s/%s/%s", path, kind, key)] = object
+		return http.StatusCreated, object
+	})
+	f.route("PUT", item, func(params []string, body fakeObject) (int, interface{}) {
+		path, err := f.resolve(parent, params[0])
+		if err != nil {
+			return 0, err
+		}
+		object, ok := f.objects[fmt.Sprintf("%s/%s/%s", path, kind, url.PathEscape(params[1]))]
+		if !ok {
+			return 0, fakeNotFound(kind)
+		}
+		updated := fakeObject{}
+		for k, v := range object {
+			updated[k] = v
+		}
+		for k, v := range body {
+			if k != c.key {
+				updated[k] = v
+			}
+		}
+		if c.prepare != nil {
+			if err := c.prepare(updated); err != nil {
+				return 0, err
+			}
+		}
+		f.objects[fmt.Sprintf("%s/%s/%s", path, kind, url.PathEscape(params[1]))] = updated
+		return http.StatusOK, c.render(updated)
+	})
+	f.route("DELETE", item, func(params []string, body fakeObject) (int, interface{}) {
+		path, err := f.resolve(parent, params[0])
+		if err != nil {
+			return 0, err
+		}
+		key := fmt.Sprintf("%s/%s/%s", path, kind, url.PathEscape(params[1]))
+		if _, ok := f.objects[key]; !ok {
+			return 0, fakeNotFound(kind)
+		}
+		delete(f.objects, key)
+		return http.StatusNoContent, nil
+	})
+}
+
+// ServeHTTP implements http.Handler.
+func (f *fakeGitlab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.requests = append(f.requests, r.Method+" "+r.URL.EscapedPath())
+	status, payload := f.serve(r)
+	if e, ok := payload.(*fakeError); ok {
+		status, payload = e.status, map[string]interface{}{"message": e.message}
+	}
+
+	if list, ok := payload.([]fakeObject); ok {
+		payload = f.paginate(w, r, list)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if payload != nil {
+		json.NewEncoder(w).Encode(payload)
+	}
+}
+
+func (f *fakeGitlab) serve(r *http.Request) (int, interface{}) {
+	if !strings.HasPrefix(r.URL.EscapedPath(), "/api/v4/") {
+		return 0, fakeNotFound("API")
+	}
+
+	token := r.Header.Get("PRIVATE-TOKEN")
+	if token == "" {
+		token = r.Header.Get("JOB-TOKEN")
+	}
+	if token == "" {
+		token = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	if token != fakeGitlabToken {
+		return 0, &fakeError{http.StatusUnauthorized, "401 Unauthorized"}
+	}
+
+	f.user = 1
+	if sudo := r.Header.Get("SUDO"); sudo != "" {
+		user, ok := f.findUser(sudo)
+		if !ok {
+			return 0, fakeNotFound("User")
+		}
+		f.user = user
+	}
+
+	body, err := fakeParams(r)
+	if err != nil {
+		return 0, &fakeError{http.StatusBadRequest, err.Error()}
+	}
+
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4/"), "/"), "/")
+	for _, route := range f.routes {
+		if route.method != r.Method || len(route.pattern) != len(segments) {
+			continue
+		}
+		params := []string{}
+		matched := true
+		for i, element := range route.pattern {
+			segment, err := url.PathUnescape(segments[i])
+			if err != nil {
+				return 0, &fakeError{http.StatusBadRequest, err.Error()}
+			}
+			if strings.HasPrefix(element, ":") {
+				params = append(params, segment)
+			} else if element != segment {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return route.handler(params, body)
+		}
+	}
+	return 0, fakeNotFound("Route")
+}
+
+// fakeParams merges the parameters in the query string, in the form and in
+// the JSON body of the request; as query string and form values carry no type
+// information, booleans and integers are recognised by their format.
+func fakeParams(r *http.Request) (fakeObject, error) {
+	params := fakeObject{}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	values := r.URL.Query()
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") && len(data) > 0 {
+		decoder := json.NewDecoder(strings.NewReader(string(data)))
+		decoder.UseNumber()
+		if err := decoder.Decode(&params); err != nil {
+			return nil, err
+		}
+		for k, v := range params {
+			params[k] = fakeNumbers(v)
+		}
+	} else if len(data) > 0 {
+		form, err := url.ParseQuery(string(data))
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range form {
+			values[k] = v
+		}
+	}
+
+	for k, v := range values {
+		k = strings.TrimSuffix(k, "[]")
+		if strings.HasSuffix(k, "]") {
+			// nested values, e.g. variables[0][key], are kept as strings
+			params[k] = v[0]
+			continue
+		}
+		if len(v) > 1 {
+			params[k] = v
+			continue
+		}
+		switch value := v[0]; {
+		case value == "true" || value == "false":
+			params[k] = value == "true"
+		default:
+			if i, err := strconv.Atoi(value); err == nil {
+				params[k] = i
+			} else {
+				params[k] = value
+			}
+		}
+	}
+	return params, nil
+}
+
+// fakeNumbers converts the JSON numbers in the given value, and in any value
+// nested in it, into integers or floats.
+func fakeNumbers(v interface{}) interface{} {
+	switch value := v.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return int(i)
+		}
+		f, _ := value.Float64()
+		return f
+	case []interface{}:
+		for i, item := range value {
+			value[i] = fakeNumbers(item)
+		}
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = fakeNumbers(item)
+		}
+	}
+	return v
+}
+
+// paginate serves a page of the given list, as requested through the page
+// and per_page parameters, and sets the pagination headers.
+func (f *fakeGitlab) paginate(w http.ResponseWriter, r *http.Request, list []fakeObject) []fakeObject {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 {
+		perPage = 20
+	}
+	pages := (len(list) + perPage - 1) / perPage
+
+	w.Header().Set("X-Page", strconv.Itoa(page))
+	w.Header().Set("X-Per-Page", strconv.Itoa(perPage))
+	w.Header().Set("X-Total", strconv.Itoa(len(list)))
+	w.Header().Set("X-Total-Pages", strconv.Itoa(pages))
+	if page < pages {
+		w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+	}
+
+	start := (page - 1) * perPage
+	if start > len(list) {
+		start = len(list)
+	}
+	end := start + perPage
+	if end > len(list) {
+		end = len(list)
+	}
+	return list[start:end]
+}
+
+// id returns a new, unique object ID.
+func (f *fakeGitlab) id() int {
+	f.nextID++
+	return f.nextID
+}
+
+// list returns the objects directly under the given path, sorted by key.
+func (f *fakeGitlab) list(path string) []fakeObject {
+	keys := []string{}
+	for key := range f.objects {
+		if strings.HasPrefix(key, path+"/") && !strings.Contains(strings.TrimPrefix(key, path+"/"), "/") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	list := []fakeObject{}
+	for _, key := range keys {
+		list = append(list, f.objects[key])
+	}
+	return list
+}
+
+// resolve returns the path of the project or group with the given ID, which
+// can be either numeric or a full path.
+func (f *fakeGitlab) resolve(kind, id string) (string, *fakeError) {
+	if _, err := strconv.Atoi(id); err == nil {
+		path := fmt.Sprintf("%s/%s", kind, id)
+		if _, ok := f.objects[path]; ok {
+			return path, nil
+		}
+	} else {
+		for _, object := range f.list(kind) {
+			if f.fullPath(kind, object) == id {
+				return fmt.Sprintf("%s/%v", kind, object["id"]), nil
+			}
+		}
+	}
+	if kind == "groups" {
+		return "", fakeNotFound("Group")
+	}
+	return "", fakeNotFound("Project")
+}
+
+// findUser returns the ID of the user with the given ID or username.
+func (f *fakeGitlab) findUser(user string) (int, bool) {
+	for _, object := range f.list("users") {
+		if fmt.Sprintf("%v", object["id"]) == user || object["username"] == user {
+			return object["id"].(int), true
+		}
+	}
+	return 0, false
+}
+
+// namespace returns the namespace with the given ID, as it is served.
+func (f *fakeGitlab) namespace(id int) (fakeObject, bool) {
+	if user, ok := f.objects[fmt.Sprintf("users/%d", id)]; ok {
+		return fakeObject{
+			"id":        id,
+			"name":      user["username"],
+			"path":      user["username"],
+			"kind":      "user",
+			"full_path": user["username"],
+		}, true
+	}
+	if group, ok := f.objects[fmt.Sprintf("groups/%d", id)]; ok {
+		return fakeObject{
+			"id":        id,
+			"name":      group["name"],
+			"path":      group["path"],
+			"kind":      "group",
+			"full_path": f.fullPath("groups", group),
+			"parent_id": group["parent_id"],
+		}, true
+	}
+	return nil, false
+}
+
+// fullPath returns the full path of a group or project, which depends on the
+// namespace it is in.
+func (f *fakeGitlab) fullPath(kind string, object fakeObject) string {
+	parent := 0
+	switch kind {
+	case "groups":
+		parent, _ = object["parent_id"].(int)
+	case "projects":
+		parent, _ = object["namespace_id"].(int)
+	}
+	if namespace, ok := f.namespace(parent); ok {
+		return fmt.Sprintf("%s/%s", namespace["full_path"], object["path"])
+	}
+	return fmt.Sprintf("%s", object["path"])
+}
+
+// fullName returns the full name of a group, which includes the name of its
+// parent groups.
+func (f *fakeGitlab) fullName(group fakeObject) string {
+	if parent, ok := f.objects[fmt.Sprintf("groups/%v", group["parent_id"])]; ok {
+		return fmt.Sprintf("%s / %s", f.fullName(parent), group["name"])
+	}
+	return fmt.Sprintf("%s", group["name"])
+}
+
+func (f *fakeGitlab) getCurrentUser(params []string, body fakeObject) (int, interface{}) {
+	return http.StatusOK, f.objects[fmt.Sprintf("users/%d", f.user)]
+}
+
+func (f *fakeGitlab) getUser(params []string, body fakeObject) (int, interface{}) {
+	user, ok := f.objects["users/"+params[0]]
+	if !ok {
+		return 0, fakeNotFound("User")
+	}
+	return http.StatusOK, user
+}
+
+func (f *fakeGitlab) listNamespaces(params []string, body fakeObject) (int, interface{}) {
+	search, _ := body["search"].(string)
+	namespaces := []fakeObject{}
+	for _, kind := range []string{"users", "groups"} {
+		for _, object := range f.list(kind) {
+			namespace, _ := f.namespace(object["id"].(int))
+			if search == "" || strings.Contains(namespace["path"].(string), search) || strings.Contains(namespace["name"].(string), search) {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+	}
+	return http.StatusOK, namespaces
+}
+
+// prepareMember checks that the user and the access level of a group or
+// project member are valid, and fills in the details of the user.
+func (f *fakeGitlab) prepareMember(member fakeObject) *fakeError {
+	user, ok := f.objects[fmt.Sprintf("users/%v", member["id"])]
+	if !ok {
+		return fakeNotFound("User")
+	}
+	if level, _ := member["access_level"].(int); accessLevelNames[level] == "" {
+		return fakeBadRequest("access_level", "does not have a valid value")
+	}
+	for _, field := range []string{"username", "name", "state"} {
+		member[field] = user[field]
+	}
+	if member["expires_at"] == "" {
+		member["expires_at"] = nil
+	}
+	return nil
+}
+
+// sharedWithGroups returns the groups the project or group at the given path
+// is shared with, as they are served.
+func (f *fakeGitlab) sharedWithGroups(path string) []fakeObject {
+	shares := []fakeObject{}
+	for _, share := range f.list(path + "/share") {
+		group := f.objects[fmt.Sprintf("groups/%v", share["group_id"])]
+		shares = append(shares, fakeObject{
+			"group_id":           share["group_id"],
+			"group_name":         group["name"],
+			"group_full_path":    f.fullPath("groups", group),
+			"group_access_level": share["group_access_level"],
+			"expires_at":         share["expires_at"],
+		})
+	}
+	return shares
+}
+
+func (f *fakeGitlab) share(kind string, params []string, body fakeObject) (int, interface{}) {
+	path, err := f.resolve(kind, params[0])
+	if err != nil {
+		return 0, err
+	}
+	group, ok := body["group_id"].(int)
+	if !ok {
+		return 0, fakeBadRequest("group_id", "is missing")
+	}
+	if _, ok := f.objects[fmt.Sprintf("groups/%d", group)]; !ok {
+		return 0, fakeNotFound("Group")
+	}
+	level, _ := body["group_access"].(int)
+	if accessLevelNames[level] == "" {
+		return 0, fakeBadRequest("group_access", "does not have a valid value")
+	}
+	key := fmt.Sprintf("%s/share/%d", path, group)
+	if _, ok := f.objects[key]; ok {
+		return 0, fakeBadRequest("group_id", "has already been taken")
+	}
+	share := fakeObject{
+		"id":                 f.id(),
+		"group_id":           group,
+		"group_access_level": level,
+		"expires_at":         body["expires_at"],
+	}
+	f.objects[key] = share
+	return http.StatusCreated, share
+}
+
+func (f *fakeGitlab) unshare(kind string, params []string, body fakeObject) (int, interface{}) {
+	path, err := f.resolve(kind, params[0])
+	if err != nil {
+		return 0, err
+	}
+	key := fmt.Sprintf("%s/share/%s", path, params[1])
+	if _, ok := f.objects[key]; !ok {
+		return 0, fakeNotFound("Group Link")
+	}
+	delete(f.objects, key)
+	return http.StatusNoContent, nil
+}
+
+// prepareDeployToken validates the scopes of a deploy token, generates its
+// username if not given, and turns its expiration date into a time.
+func (f *fakeGitlab) prepareDeployToken(object fakeObject) *fakeError {
+	scopes, _ := object["scopes"].([]interface{})
+	if len(scopes) == 0 {
+		return fakeBadRequest("scopes", "can't be blank")
+	}
+	if object["username"] == nil || object["username"] == "" {
+		object["username"] = fmt.Sprintf("gitlab+deploy-token-%v", object["id"])
+	}
+	if date, ok := object["expires_at"].(string); ok && len(date) == len("2006-01-02") {
+		object["expires_at"] = date + "T00:00:00.000Z"
+	}
+	return nil
+}
+
+// preparePipelineSchedule validates the cron expression of a pipeline
+// schedule, and sets when it runs next.
+func preparePipelineSchedule(object fakeObject) *fakeError {
+	for _, field := range []string{"description", "ref", "cron"} {
+		if object[field] == nil {
+			return fakeBadRequest(field, "is missing")
+		}
+	}
+	if _, errors := validateCron(fmt.Sprintf("%v", object["cron"]), "cron"); len(errors) > 0 {
+		return fakeBadRequest("cron", "is invalid syntax")
+	}
+	object["next_run_at"] = time.Now().UTC().Add(time.Hour).Truncate(time.Hour).Format(time.RFC3339)
+	return nil
+}
+
+// preparePipelineTrigger generates the token of a new pipeline trigger, owned
+// by the current user.
+func (f *fakeGitlab) preparePipelineTrigger(object fakeObject) *fakeError {
+	if object["description"] == nil {
+		return fakeBadRequest("description", "is missing")
+	}
+	if object["token"] == nil {
+		object["token"] = fmt.Sprintf("fake-trigger-%d", f.id())
+		object["owner"] = fakeObject{"id": f.user}
+	}
+	return nil
+}
+
+// pipelineScheduleVariable returns the path of a pipeline schedule, and the
+// index of its variable with the given key, or -1 if there is none.
+func (f *fakeGitlab) pipelineScheduleVariable(params []string, key string) (string, int, *fakeError) {
+	project, err := f.resolve("projects", params[0])
+	if err != nil {
+		return "", 0, err
+	}
+	path := fmt.Sprintf("%s/pipeline_schedules/%s", project, params[1])
+	schedule, ok := f.objects[path]
+	if !ok {
+		return "", 0, fakeNotFound("Pipeline Schedule")
+	}
+	for i, v := range schedule["variables"].([]fakeObject) {
+		if v["key"] == key {
+			return path, i, nil
+		}
+	}
+	return path, -1, nil
+}
+
+func (f *fakeGitlab) createPipelineScheduleVariable(params []string, body fakeObject) (int, interface{}) {
+	key := fmt.Sprintf("%v", body["key"])
+	path, i, err := f.pipelineScheduleVariable(params, key)
+	if err != nil {
+		return 0, err
+	}
+	if i >= 0 {
+		return 0, fakeBadRequest("key", "has already been taken")
+	}
+	v := fakeObject{"key": key, "value": fmt.Sprintf("%v", body["value"]), "variable_type": "env_var"}
+	if body["variable_type"] != nil {
+		v["variable_type"] = body["variable_type"]
+	}
+	f.objects[path]["variables"] = append(f.objects[path]["variables"].([]fakeObject), v)
+	return http.StatusCreated, v
+}
+
+func (f *fakeGitlab) editPipelineScheduleVariable(params []string, body fakeObject) (int, interface{}) {
+	path, i, err := f.pipelineScheduleVariable(params, params[2])
+	if err != nil {
+		return 0, err
+	}
+	if i < 0 {
+		return 0, fakeNotFound("Variable")
+	}
+	v := f.objects[path]["variables"].([]fakeObject)[i]
+	if body["value"] != nil {
+		v["value"] = fmt.Sprintf("%v", body["value"])
+	}
+	if body["variable_type"] != nil {
+		v["variable_type"] = body["variable_type"]
+	}
+	return http.StatusOK, v
+}
+
+func (f *fakeGitlab) deletePipelineScheduleVariable(params []string, body fakeObject) (int, interface{}) {
+	path, i, err := f.pipelineScheduleVariable(params, params[2])
+	if err != nil {
+		return 0, err
+	}
+	if i < 0 {
+		return 0, fakeNotFound("Variable")
+	}
+	variables := f.objects[path]["variables"].([]fakeObject)
+	f.objects[path]["variables"] = append(variables[:i:i], variables[i+1:]...)
+	return http.StatusAccepted, nil
+}
+
+// prepareProtection returns a function turning the access levels and the
+// allowed users and groups given when protecting a branch or tag, for each of
+// the given actions, into the lists of allowances that are served.
+func (f *fakeGitlab) prepareProtection(actions ...string) func(object fakeObject) *fakeError {
+	descriptions := map[int]string{0: "No one", 30: "Developers + Masters", 40: "Masters", 60: "Admins"}
+
+	return func(object fakeObject) *fakeError {
+		for _, action := range actions {
+			if _, ok := object[action+"_access_levels"]; ok {
+				continue
+			}
+			level := 40
+			if v, ok := object[action+"_access_level"]; ok {
+				level, _ = v.(int)
+				if _, ok := descriptions[level]; !ok {
+					return fakeBadRequest(action+"_access_level", "does not have a valid value")
+				}
+			}
+			allowances := []fakeObject{{
+				"access_level":             level,
+				"access_level_description": descriptions[level],
+			}}
+			allowed, _ := object["allowed_to_"+action].([]interface{})
+			for _, v := range allowed {
+				allowance := fakeObject{"access_level": 40}
+				for k, id := range v.(map[string]interface{}) {
+					allowance[k] = id
+				}
+				allowances = append(allowances, allowance)
+			}
+			object[action+"_access_levels"] = allowances
+			delete(object, action+"_access_level")
+			delete(object, "allowed_to_"+action)
+		}
+		return nil
+	}
+}
+
+// group returns a group as it is served.
+func (f *fakeGitlab) group(group fakeObject) fakeObject {
+	served := fakeObject{}
+	for k, v := range group {
+		served[k] = v
+	}
+	served["full_path"] = f.fullPath("groups", group)
+	served["full_name"] = f.fullName(group)
+	served["shared_with_groups"] = f.sharedWithGroups(fmt.Sprintf("groups/%v", group["id"]))
+	served["web_url"] = fmt.Sprintf("%s/groups/%s", f.server.URL, served["full_path"])
+	return served
+}
+
+func (f *fakeGitlab) createGroup(params []string, body fakeObject) (int, interface{}) {
+	if body["name"] == nil {
+		return 0, fakeBadRequest("name", "is missing")
+	}
+	if body["path"] == nil {
+		return 0, fakeBadRequest("path", "is missing")
+	}
+	if parent, ok := body["parent_id"].(int); ok && parent != 0 {
+		if _, ok := f.objects[fmt.Sprintf("groups/%d", parent)]; !ok {
+			return 0, fakeNotFound("Parent Group")
+		}
+	}
+
+	group := fakeObject{
+		"description":            "",
+		"visibility":             "private",
+		"lfs_enabled":            true,
+		"request_access_enabled": false,
+		"parent_id":              nil,
+	}
+	for k, v := range body {
+		group[k] = v
+	}
+	for _, other := range f.list("groups") {
+		if other["path"] == group["path"] && other["parent_id"] == group["parent_id"] {
+			return 0, fakeBadRequest("path", "has already been taken")
+		}
+	}
+	group["id"] = f.id()
+	f.objects[fmt.Sprintf("groups/%d", group["id"])] = group
+
+	// the creator becomes the owner of the group
+	owner := fakeObject{"id": f.user, "access_level": 50, "expires_at": nil}
+	f.prepareMember(owner)
+	f.objects[fmt.Sprintf("groups/%d/members/%d", group["id"], f.user)] = owner
+	return http.StatusCreated, f.group(group)
+}
+
+func (f *fakeGitlab) getGroup(params []string, body fakeObject) (int, interface{}) {
+	path, err := f.resolve("groups", params[0])
+	if err != nil {
+		return 0, err
+	}
+	return http.StatusOK, f.group(f.objects[path])
+}
+
+func (f *fakeGitlab) updateGroup(params []string, body fakeObject) (int, interface{}) {
+	path, err := f.resolve("groups", params[0])
+	if err != nil {
+		return 0, err
+	}
+	group := f.objects[path]
+	for k, v := range body {
+		if k != "id" && k != "parent_id" {
+			group[k] = v
+		}
+	}
+	return http.StatusOK, f.group(group)
+}
+
+func (f *fakeGitlab) deleteGroup(params []string, body fakeObject) (int, interface{}) {
+	path, err := f.resolve("groups", params[0])
+	if err != nil {
+		return 0, err
+	}
+	// projects and subgroups go along with the group
+	id := f.objects[path]["id"]
+	for key, object := range f.objects {
+		if strings.HasPrefix(key, "groups/") && object["parent_id"] == id {
+			f.deleteGroup([]string{fmt.Sprintf("%v", object["id"])}, nil)
+		}
+	}
+	for key, object := range f.objects {
+		if strings.HasPrefix(key, "projects/") && object["namespace_id"] == id {
+			f.deleteProject([]string{fmt.Sprintf("%v", object["id"])}, nil)
+		}
+	}
+	f.deleteAll(path)
+	for key := range f.objects {
+		if strings.HasSuffix(key, fmt.Sprintf("/share/%v", id)) {
+			delete(f.objects, key)
+		}
+	}
+	return http.StatusAccepted, map[string]string{"message": "202 Accepted"}
+}
+
+func (f *fakeGitlab) transferGroup(params []string, body fakeObject) (int, interface{}) {
+	path, err := f.resolve("groups", params[0])
+	if err != nil {
+		return 0, err
+	}
+	group := f.objects[path]
+	parent, _ := body["group_id"].(int)
+	if parent == 0 {
+		group["parent_id"] = nil
+		return http.StatusCreated, f.group(group)
+	}
+	if _, ok := f.objects[fmt.Sprintf("groups/%d", parent)]; !ok {
+		return 0, fakeNotFound("Group")
+	}
+	group["parent_id"] = parent
+	return http.StatusCreated, f.group(group)
+}
+
+// project returns a project as it is served.
+func (f *fakeGitlab) project(project fakeObject) fakeObject {
+	served := fakeObject{}
+	for k, v := range project {
+		served[k] = v
+	}
+	namespace, _ := f.namespace(project["namespace_id"].(int))
+	delete(served, "namespace_id")
+	served["namespace"] = namespace
+	served["path_with_namespace"] = f.fullPath("projects", project)
+	served["name_with_namespace"] = fmt.Sprintf("%s / %s", namespace["name"], project["name"])
+	served["web_url"] = fmt.Sprintf("%s/%s", f.server.URL, served["path_with_namespace"])
+	served["http_url_to_repo"] = fmt.Sprintf("%s/%s.git", f.server.URL, served["path_with_namespace"])
+	served["ssh_url_to_repo"] = fmt.Sprintf("git@localhost:%s.git", served["path_with_namespace"])
+	served["shared_with_groups"] = f.sharedWithGroups(fmt.Sprintf("projects/%v", project["id"]))
+	return served
+}
+
+func (f *fakeGitlab) createProject(params []string, body fakeObject) (int, interface{}) {
+	if body["name"] == nil {
+		return 0, fakeBadRequest("name", "is missing")
+	}
+
+	project := fakeObject{
+		"description":                           "",
+		"default_branch":                        nil,
+		"visibility":                            "private",
+		"issues_enabled":                        true,
+		"merge_requests_enabled":                true,
+		"jobs_enabled":                          true,
+		"wiki_enabled":                          true,
+		"snippets_enabled":                      true,
+		"container_registry_enabled":            true,
+		"shared_runners_enabled":                true,
+		"public_jobs":                           true,
+		"only_allow_merge_if_pipeline_succeeds": false,
+		"only_allow_merge_if_all_discussions_are_resolved": false,
+		"lfs_enabled":            true,
+		"request_access_enabled": false,
+		"archived":               false,
+		"creator_id":             f.user,
+		"namespace_id":           f.user,
+		"created_at":             time.Now().UTC().Format(time.RFC3339),
+	}
+	for k, v := range body {
+		project[k] = v
+	}
+	if project["path"] == nil {
+		project["path"] = strings.ToLower(strings.Replace(project["name"].(string), " ", "-", -1))
+	}
+	if _, ok := f.namespace(project["namespace_id"].(int)); !ok {
+		return 0, fakeNotFound("Namespace")
+	}
+	for _, other := range f.list("projects") {
+		if other["path"] == project["path"] && other["namespace_id"] == project["namespace_id"] {
+			return 0, fakeBadRequest("path", "has already been taken")
+		}
+	}
+	project["id"] = f.id()
+	f.objects[fmt.Sprintf("projects/%d", project["id"])] = project
+	return http.StatusCreated, f.project(project)
+}
+
+func (f *fakeGitlab) getProject(params []string, body fakeObject) (int, interface{}) {
+	path, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, err
+	}
+	return http.StatusOK, f.project(f.objects[path])
+}
+
+func (f *fakeGitlab) updateProject(params []string, body fakeObject) (int, interface{}) {
+	path, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, err
+	}
+	project := f.objects[path]
+	for k, v := range body {
+		if k != "id" && k != "namespace_id" {
+			project[k] = v
+		}
+	}
+	return http.StatusOK, f.project(project)
+}
+
+func (f *fakeGitlab) deleteProject(params []string, body fakeObject) (int, interface{}) {
+	path, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, err
+	}
+	f.deleteAll(path)
+	return http.StatusAccepted, map[string]string{"message": "202 Accepted"}
+}
+
+func (f *fakeGitlab) transferProject(params []string, body fakeObject) (int, interface{}) {
+	path, err := f.resolve("projects", params[0])
+	if err != nil {
+		return 0, err
+	}
+	namespace, _ := body["namespace"].(int)
+	if _, ok := f.namespace(namespace); !ok {
+		return 0, fakeNotFound("Namespace")
+	}
+	f.objects[path]["namespace_id"] = namespace
+	return http.StatusOK, f.project(f.objects[path])
+}
+
+// deleteAll removes the object at the given path and everything nested
+// under it.
+func (f *fakeGitlab) deleteAll(path string) {
+	for key := range f.objects {
+		if key == path || strings.HasPrefix(key, path+"/") {
+			delete(f.objects, key)
+		}
+	}
+}
+
+// The following helpers are meant to be used by tests, to set up the fake
+// gitlab and to simulate changes made outside of Terraform.
+
+// addUser adds a user and returns its ID, which is also its namespace ID.
+func (f *fakeGitlab) addUser(username string) int {
+	f.Lock()
+	defer f.Unlock()
+
+	id := f.id()
+	f.objects[fmt.Sprintf("users/%d", id)] = fakeObject{
+		"id":       id,
+		"username": username,
+		"name":     username,
+		"state":    "active",
+		"is_admin": false,
+	}
+	return id
+}
+
+// get returns a copy of the object at the given path (e.g. "projects/12"),
+// or nil if there is none.
+func (f *fakeGitlab) get(path string) fakeObject {
+	f.Lock()
+	defer f.Unlock()
+
+	object, ok := f.objects[path]
+	if !ok {
+		return nil
+	}
+	copy := fakeObject{}
+	for k, v := range object {
+		copy[k] = v
+	}
+	return copy
+}
+
+// put adds an object at the given path, or replaces the one that is there.
+func (f *fakeGitlab) put(path string, object fakeObject) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.objects[path] = object
+}
+
+// set changes the fields of the object at the given path.
+func (f *fakeGitlab) set(path string, changes fakeObject) {
+	f.Lock()
+	defer f.Unlock()
+
+	object, ok := f.objects[path]
+	if !ok {
+		f.t.Fatalf("fake gitlab: no object at %s", path)
+	}
+	for k, v := range changes {
+		object[k] = v
+	}
+}
+
+// remove deletes the object at the given path, and everything under it.
+func (f *fakeGitlab) remove(path string) {
+	f.Lock()
+	defer f.Unlock()
+
+	if _, ok := f.objects[path]; !ok {
+		f.t.Fatalf("fake gitlab: no object at %s", path)
+	}
+	f.deleteAll(path)
+}
+
+// count returns the number of objects directly under the given path (e.g.
+// "projects" or "projects/12/hooks").
+func (f *fakeGitlab) count(path string) int {
+	f.Lock()
+	defer f.Unlock()
+
+	return len(f.list(path))
+}
+
+// countRequests returns the number of requests served so far with the given
+// method, whose path contains the given string.
+func (f *fakeGitlab) countRequests(method, path string) int {
+	f.Lock()
+	defer f.Unlock()
+
+	n := 0
+	for _, request := range f.requests {
+		if strings.HasPrefix(request, method+" ") && strings.Contains(request, path) {
+			n++
+		}
+	}
+	return n
+}
+
+// find returns the path of the first object directly under the given path
+// whose field has the given value, or "" if there is none.
+func (f *fakeGitlab) find(path, field string, value interface{}) string {
+	f.Lock()
+	defer f.Unlock()
+
+	for key, object := range f.objects {
+		if strings.HasPrefix(key, path+"/") && !strings.Contains(strings.TrimPrefix(key, path+"/"), "/") && object[field] == value {
+			return key
+		}
+	}
+	return ""
+}
+
+// testCheckFakeGitlabObject checks the fields of the object the given
+// resource corresponds to in the fake gitlab; its path is built by filling
+// format with the values of the given attributes of the resource (e.g.
+// "projects/%s/hooks/%s" with "project" and "id").
+func testCheckFakeGitlabObject(f *fakeGitlab, n string, fields fakeObject, format string, attributes ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		values := []interface{}{}
+		for _, attribute := range attributes {
+			values = append(values, rs.Primary.Attributes[attribute])
+		}
+		path := fmt.Sprintf(format, values...)
+
+		object := f.get(path)
+		if object == nil {
+			return fmt.Errorf("No object at %s", path)
+		}
+		for field, want := range fields {
+			if got := object[field]; fmt.Sprintf("%v", got) != fmt.Sprintf("%v", want) {
+				return fmt.Errorf("%s: got %s %v; want %v", path, field, got, want)
+			}
+		}
+		return nil
+	}
+}
+
+// testCheckFakeGitlabCount checks the number of objects directly under the
+// given path.
+func testCheckFakeGitlabCount(f *fakeGitlab, path string, want int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if got := f.count(path); got != want {
+			return fmt.Errorf("got %d objects under %s; want %d", got, path, want)
+		}
+		return nil
+	}
+}
+
+// testFakeGitlabConfig returns the provider configuration pointing to the
+// given fake gitlab, to be prepended to the configuration of a unit test.
+func testFakeGitlabConfig(f *fakeGitlab) string {
+	return fmt.Sprintf(`
+provider "gitlabx" {
+  base_url = "%s"
+  token = "%s"
+  max_retries = 0
+}
+`, f.server.URL, fakeGitlabToken)
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+)
+
+// Variables are stored at ":kind/:id/variables/:key::scope", with both the
+// key and the environment scope escaped, since several variables can share a
+// key in different environments.
+
+// the values gitlab accepts for masked variables
+var fakeMaskable = regexp.MustCompile(`^[a-zA-Z0-9+/=@:.~_-]{8,}$`)
+
+func (f *fakeGitlab) variableRoutes() {
+	for _, kind := range []string{"projects", "groups"} {
+		kind := kind
+		f.route("GET", kind+"/:id/variables", func(params []string, body fakeObject) (int, interface{}) {
+			path, err := f.resolve(kind, params[0])
+			if err != nil {
+				return 0, err
+			}
+			return http.StatusOK, f.list(path + "/variables")
+		})
+		f.route("GET", kind+"/:id/variables/:key", func(params []string, body fakeObject) (int, interface{}) {
+			path, err := f.variablePath(kind, params, body)
+			if err != nil {
+				return 0, err
+			}
+			return http.StatusOK, f.objects[path]
+		})
+		f.route("POST", kind+"/:id/variables", func(params []string, body fakeObject) (int, interface{}) {
+			return f.createVariable(kind, params, body)
+		})
+		f.route("PUT", kind+"/:id/variables/:key", func(params []string, body fakeObject) (int, interface{}) {
+			return f.updateVariable(kind, params, body)
+		})
+		f.route("DELETE", kind+"/:id/variables/:key", func(params []string, body fakeObject) (int, interface{}) {
+			path, err := f.variablePath(kind, params, body)
+			if err != nil {
+				return 0, err
+			}
+			delete(f.objects, path)
+			return http.StatusNoContent, nil
+		})
+	}
+}
+
+// fakeScope returns the environment scope a request filters variables by,
+// given either as a query parameter or in a JSON body.
+func fakeScope(body fakeObject) string {
+	if scope, ok := body["filter[environment_scope]"]; ok {
+		return fmt.Sprintf("%v", scope)
+	}
+	if filter, ok := body["filter"].(map[string]interface{}); ok && filter["environment_scope"] != nil {
+		return fmt.Sprintf("%v", filter["environment_scope"])
+	}
+	return "*"
+}
+
+// variablePath returns the path of the variable a request is about.
+func (f *fakeGitlab) variablePath(kind string, params []string, body fakeObject) (string, *fakeError) {
+	path, err := f.resolve(kind, params[0])
+	if err != nil {
+		return "", err
+	}
+	path = fmt.Sprintf("%s/variables/%s:%s", path, url.PathEscape(params[1]), url.PathEscape(fakeScope(body)))
+	if _, ok := f.objects[path]; !ok {
+		return "", fakeNotFound("Variable")
+	}
+	return path, nil
+}
+
+// prepareVariable validates the given variable.
+func prepareVariable(object fakeObject) *fakeError {
+	if object["variable_type"] != "env_var" && object["variable_type"] != "file" {
+		return fakeBadRequest("variable_type", "does not have a valid value")
+	}
+	if object["masked"] == true && !fakeMaskable.MatchString(object["value"].(string)) {
+		return fakeBadRequest("value", "is invalid")
+	}
+	return nil
+}
+
+func (f *fakeGitlab) createVariable(kind string, params []string, body fakeObject) (int, interface{}) {
+	path, err := f.resolve(kind, params[0])
+	if err != nil {
+		return 0, err
+	}
+	for _, field := range []string{"key", "value"} {
+		if body[field] == nil {
+			return 0, fakeBadRequest(field, "is missing")
+		}
+	}
+
+	object := fakeObject{
+		"key":               fmt.Sprintf("%v", body["key"]),
+		"value":             fmt.Sprintf("%v", body["value"]),
+		"variable_type":     "env_var",
+		"protected":         body["protected"] == true,
+		"masked":            body["masked"] == true,
+		"environment_scope": "*",
+	}
+	if body["variable_type"] != nil {
+		object["variable_type"] = body["variable_type"]
+	}
+	if body["environment_scope"] != nil {
+		object["environment_scope"] = fmt.Sprintf("%v", body["environment_scope"])
+	}
+	if err := prepareVariable(object); err != nil {
+		return 0, err
+	}
+
+	path = fmt.Sprintf("%s/variables/%s:%s", path, url.PathEscape(object["key"].(string)), url.PathEscape(object["environment_scope"].(string)))
+	if _, ok := f.objects[path]; ok {
+		return 0, fakeBadRequest("key", fmt.Sprintf("(%s) has already been taken", object["key"]))
+	}
+	f.objects[path] = object
+	return http.StatusCreated, object
+}
+
+func (f *fakeGitlab) updateVariable(kind string, params []string, body fakeObject) (int, interface{}) {
+	path, err := f.variablePath(kind, params, body)
+	if err != nil {
+		return 0, err
+	}
+
+	updated := fakeObject{}
+	for k, v := range f.objects[path] {
+		updated[k] = v
+	}
+	if body["value"] != nil {
+		updated["value"] = fmt.Sprintf("%v", body["value"])
+	}
+	for _, field := range []string{"variable_type", "protected", "masked"} {
+		if body[field] != nil {
+			updated[field] = body[field]
+		}
+	}
+	if err := prepareVariable(updated); err != nil {
+		return 0, err
+	}
+
+	f.objects[path] = updated
+	return http.StatusOK, updated
+}
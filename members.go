@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// Group and project members are managed through the same API, under either
+// groups/:id/members or projects/:id/members; the go-gitlab client does not
+// support expiration dates, nor retrieving a single member of a group, so the
+// functions in this file perform the requests themselves. The kind argument
+// is either "groups" or "projects".
+
+// the names of the access levels, as used in the resources
+var accessLevelNames = map[int]string{
+	10: "guest",
+	20: "reporter",
+	30: "developer",
+	40: "master",
+	50: "owner",
+}
+
+// accessLevel returns the value of the access level with the given name, or
+// 0 if the name is unknown.
+func accessLevel(name string) int {
+	for value, n := range accessLevelNames {
+		if n == name {
+			return value
+		}
+	}
+	return 0
+}
+
+// member is a member of a group or project, as returned by the members API.
+type member struct {
+	ID          int    `json:"id"`
+	Username    string `json:"username"`
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	AccessLevel int    `json:"access_level"`
+	ExpiresAt   string `json:"expires_at"`
+}
+
+// memberOptions represents the available options when adding a member to a
+// group or project, or when editing it; an empty expiration date removes it.
+type memberOptions struct {
+	UserID      *int    `url:"user_id,omitempty" json:"user_id,omitempty"`
+	AccessLevel *int    `url:"access_level,omitempty" json:"access_level,omitempty"`
+	ExpiresAt   *string `url:"expires_at,omitempty" json:"expires_at,omitempty"`
+}
+
+func getMember(client *Client, kind, id string, user int, options ...gitlab.OptionFunc) (*member, *gitlab.Response, error) {
+	m := &member{}
+	response, err := doRequest(client, "GET", fmt.Sprintf("%s/%s/members/%d", kind, pathEscape(id), user), nil, m, options...)
+	if err != nil {
+		return nil, response, err
+	}
+	return m, response, nil
+}
+
+// listMembers returns all the direct members of a group or project, going
+// through all the pages of the list.
+func listMembers(client *Client, kind, id string, options ...gitlab.OptionFunc) ([]*member, *gitlab.Response, error) {
+	members := []*member{}
+	opt := &gitlab.ListOptions{PerPage: 100, Page: 1}
+	for {
+		page := []*member{}
+		response, err := doRequest(client, "GET", fmt.Sprintf("%s/%s/members", kind, pathEscape(id)), opt, &page, options...)
+		if err != nil {
+			return nil, response, err
+		}
+		members = append(members, page...)
+		if response.NextPage == 0 {
+			return members, response, nil
+		}
+		opt.Page = response.NextPage
+	}
+}
+
+func addMember(client *Client, kind, id string, opt *memberOptions, options ...gitlab.OptionFunc) (*member, error) {
+	log.Printf("[DEBUG] add user %d to gitlab %s %s", *opt.UserID, kind, id)
+
+	m := &member{}
+	if _, err := doRequest(client, "POST", fmt.Sprintf("%s/%s/members", kind, pathEscape(id)), opt, m, options...); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func editMember(client *Client, kind, id string, user int, opt *memberOptions, options ...gitlab.OptionFunc) error {
+	log.Printf("[DEBUG] update user %d in gitlab %s %s", user, kind, id)
+
+	_, err := doRequest(client, "PUT", fmt.Sprintf("%s/%s/members/%d", kind, pathEscape(id), user), opt, nil, options...)
+	return err
+}
+
+func removeMember(client *Client, kind, id string, user int, options ...gitlab.OptionFunc) error {
+	log.Printf("[DEBUG] remove user %d from gitlab %s %s", user, kind, id)
+
+	_, err := doRequest(client, "DELETE", fmt.Sprintf("%s/%s/members/%d", kind, pathEscape(id), user), nil, nil, options...)
+	return err
+}
+
+// the usernames of the bot users gitlab creates for project and group access
+// tokens
+var botUsername = regexp.MustCompile(`^(project|group)_\d+_bot(_[0-9a-f]+)?$`)
+
+// membersSchema returns the schema of the authoritative list of members of a
+// group or project, whose roles can be the given access levels.
+func membersSchema(levels []string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"member": {
+			Type:        schema.TypeSet,
+			Description: "The members; any other member is removed, unless ignored.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"user_id": {
+						Type:     schema.TypeInt,
+						Required: true,
+					},
+					"access_level": {
+						Type:         schema.TypeString,
+						Description:  fmt.Sprintf("The role of the user: one of %s.", strings.Join(levels, ", ")),
+						Required:     true,
+						ValidateFunc: validateValueFunc(levels),
+					},
+					"expires_at": {
+						Type:         schema.TypeString,
+						Description:  "The date (in the YYYY-MM-DD format) when the membership expires; by default it never does.",
+						Optional:     true,
+						ValidateFunc: validateDate,
+					},
+				},
+			},
+		},
+		"ignore_owners": {
+			Type:        schema.TypeBool,
+			Description: "Leave alone the undeclared members with the owner role, such as the creator of a group.",
+			Optional:    true,
+			Default:     false,
+		},
+		"ignore_bots": {
+			Type:        schema.TypeBool,
+			Description: "Leave alone the undeclared bot users, such as those of project and group access tokens.",
+			Optional:    true,
+			Default:     false,
+		},
+	}
+}
+
+// declaredMembers returns the members in the configuration, by user ID.
+func declaredMembers(d *schema.ResourceData) map[int]*member {
+	members := map[int]*member{}
+	for _, v := range d.Get("member").(*schema.Set).List() {
+		m := v.(map[string]interface{})
+		members[m["user_id"].(int)] = &member{
+			ID:          m["user_id"].(int),
+			AccessLevel: accessLevel(m["access_level"].(string)),
+			ExpiresAt:   m["expires_at"].(string),
+		}
+	}
+	return members
+}
+
+// ignoredMember returns whether an undeclared member must be left alone.
+func ignoredMember(d *schema.ResourceData, m *member) bool {
+	if d.Get("ignore_owners").(bool) && m.AccessLevel == 50 {
+		return true
+	}
+	if d.Get("ignore_bots").(bool) && botUsername.MatchString(m.Username) {
+		return true
+	}
+	return false
+}
+
+// setMembersToState sets the actual members of a group or project into the
+// state, except for the ignored ones; members added outside of Terraform show
+// up there, so that the next plan removes them.
+func setMembersToState(d *schema.ResourceData, members []*member) error {
+	declared := declaredMembers(d)
+	set := []interface{}{}
+	for _, m := range members {
+		if _, ok := declared[m.ID]; !ok && ignoredMember(d, m) {
+			continue
+		}
+		set = append(set, map[string]interface{}{
+			"user_id":      m.ID,
+			"access_level": accessLevelNames[m.AccessLevel],
+			"expires_at":   m.ExpiresAt,
+		})
+	}
+	return d.Set("member", set)
+}
+
+// applyMembers makes the members of a group or project match the declared
+// ones: missing members are added, those whose role or expiration date differ
+// are updated and, unless ignored, the undeclared ones are removed.
+func applyMembers(client *Client, kind, id string, d *schema.ResourceData) error {
+	members, _, err := listMembers(client, kind, id)
+	if err != nil {
+		return err
+	}
+	actual := map[int]*member{}
+	for _, m := range members {
+		actual[m.ID] = m
+	}
+
+	declared := declaredMembers(d)
+	for user, want := range declared {
+		got, ok := actual[user]
+		switch {
+		case !ok:
+			options := &memberOptions{
+				UserID:      gitlab.Int(user),
+				AccessLevel: gitlab.Int(want.AccessLevel),
+			}
+			if want.ExpiresAt != "" {
+				options.ExpiresAt = gitlab.String(want.ExpiresAt)
+			}
+			if _, err := addMember(client, kind, id, options); err != nil {
+				return fmt.Errorf("Error adding user %d to %s %s: %s", user, kind, id, err)
+			}
+		case got.AccessLevel != want.AccessLevel || got.ExpiresAt != want.ExpiresAt:
+			options := &memberOptions{
+				AccessLevel: gitlab.Int(want.AccessLevel),
+				ExpiresAt:   gitlab.String(want.ExpiresAt),
+			}
+			if err := editMember(client, kind, id, user, options); err != nil {
+				return fmt.Errorf("Error updating user %d in %s %s: %s", user, kind, id, err)
+			}
+		}
+	}
+
+	for user, m := range actual {
+		if _, ok := declared[user]; ok || ignoredMember(d, m) {
+			continue
+		}
+		if err := removeMember(client, kind, id, user); err != nil {
+			return fmt.Errorf("Error removing user %d from %s %s: %s", user, kind, id, err)
+		}
+	}
+	return nil
+}
+
+// removeMembers removes the members of a group or project that are managed by
+// Terraform, leaving the ignored ones alone.
+func removeMembers(client *Client, kind, id string, d *schema.ResourceData) error {
+	for user := range declaredMembers(d) {
+		if err := removeMember(client, kind, id, user); err != nil {
+			return fmt.Errorf("Error removing user %d from %s %s: %s", user, kind, id, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// Pipeline schedules run the pipeline of a project on a ref periodically, as
+// set by a cron expression, with their own variables; they were introduced by
+// API v4, and are not part of the go-gitlab client, so the functions in this
+// file perform the requests themselves, and fail on v3.
+
+// pipelineSchedule is a pipeline schedule of a project.
+type pipelineSchedule struct {
+	ID           int                         `json:"id"`
+	Description  string                      `json:"description"`
+	Ref          string                      `json:"ref"`
+	Cron         string                      `json:"cron"`
+	CronTimezone string                      `json:"cron_timezone"`
+	NextRunAt    string                      `json:"next_run_at"`
+	Active       bool                        `json:"active"`
+	Variables    []*pipelineScheduleVariable `json:"variables"`
+}
+
+// pipelineScheduleVariable is a variable set in the pipelines run by a
+// schedule.
+type pipelineScheduleVariable struct {
+	Key          string `json:"key"`
+	Value        string `json:"value"`
+	VariableType string `json:"variable_type"`
+}
+
+// pipelineScheduleOptions represents the available options when creating or
+// editing a pipeline schedule.
+type pipelineScheduleOptions struct {
+	Description  *string `url:"description,omitempty" json:"description,omitempty"`
+	Ref          *string `url:"ref,omitempty" json:"ref,omitempty"`
+	Cron         *string `url:"cron,omitempty" json:"cron,omitempty"`
+	CronTimezone *string `url:"cron_timezone,omitempty" json:"cron_timezone,omitempty"`
+	Active       *bool   `url:"active,omitempty" json:"active,omitempty"`
+}
+
+// pipelineScheduleVariableOptions represents the available options when
+// creating or editing a variable of a pipeline schedule.
+type pipelineScheduleVariableOptions struct {
+	Key          *string `url:"key,omitempty" json:"key,omitempty"`
+	Value        *string `url:"value,omitempty" json:"value,omitempty"`
+	VariableType *string `url:"variable_type,omitempty" json:"variable_type,omitempty"`
+}
+
+func getPipelineSchedule(client *Client, pid string, schedule int, options ...gitlab.OptionFunc) (*pipelineSchedule, *gitlab.Response, error) {
+	if !client.isV4() {
+		return nil, nil, fmt.Errorf("Pipeline schedules require gitlab API v4")
+	}
+
+	s := &pipelineSchedule{}
+	response, err := doRequest(client, "GET", fmt.Sprintf("projects/%s/pipeline_schedules/%d", pathEscape(pid), schedule), nil, s, options...)
+	if err != nil {
+		return nil, response, err
+	}
+	return s, response, nil
+}
+
+func createPipelineSchedule(client *Client, pid string, opt *pipelineScheduleOptions, options ...gitlab.OptionFunc) (*pipelineSchedule, error) {
+	if !client.isV4() {
+		return nil, fmt.Errorf("Pipeline schedules require gitlab API v4")
+	}
+
+	log.Printf("[DEBUG] create pipeline schedule %q in gitlab project %s", *opt.Description, pid)
+
+	s := &pipelineSchedule{}
+	if _, err := doRequest(client, "POST", fmt.Sprintf("projects/%s/pipeline_schedules", pathEscape(pid)), opt, s, options...); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func editPipelineSchedule(client *Client, pid string, schedule int, opt *pipelineScheduleOptions, options ...gitlab.OptionFunc) error {
+	if !client.isV4() {
+		return fmt.Errorf("Pipeline schedules require gitlab API v4")
+	}
+
+	log.Printf("[DEBUG] update pipeline schedule %d of gitlab project %s", schedule, pid)
+
+	_, err := doRequest(client, "PUT", fmt.Sprintf("projects/%s/pipeline_schedules/%d", pathEscape(pid), schedule), opt, nil, options...)
+	return err
+}
+
+func deletePipelineSchedule(client *Client, pid string, schedule int, options ...gitlab.OptionFunc) error {
+	log.Printf("[DEBUG] delete pipeline schedule %d of gitlab project %s", schedule, pid)
+
+	_, err := doRequest(client, "DELETE", fmt.Sprintf("projects/%s/pipeline_schedules/%d", pathEscape(pid), schedule), nil, nil, options...)
+	return err
+}
+
+func createPipelineScheduleVariable(client *Client, pid string, schedule int, opt *pipelineScheduleVariableOptions, options ...gitlab.OptionFunc) error {
+	log.Printf("[DEBUG] create variable %s of pipeline schedule %d of gitlab project %s", *opt.Key, schedule, pid)
+
+	_, err := doRequest(client, "POST", fmt.Sprintf("projects/%s/pipeline_schedules/%d/variables", pathEscape(pid), schedule), opt, nil, options...)
+	return err
+}
+
+func editPipelineScheduleVariable(client *Client, pid string, schedule int, key string, opt *pipelineScheduleVariableOptions, options ...gitlab.OptionFunc) error {
+	log.Printf("[DEBUG] update variable %s of pipeline schedule %d of gitlab project %s", key, schedule, pid)
+
+	_, err := doRequest(client, "PUT", fmt.Sprintf("projects/%s/pipeline_schedules/%d/variables/%s", pathEscape(pid), schedule, pathEscape(key)), opt, nil, options...)
+	return err
+}
+
+func deletePipelineScheduleVariable(client *Client, pid string, schedule int, key string, options ...gitlab.OptionFunc) error {
+	log.Printf("[DEBUG] delete variable %s of pipeline schedule %d of gitlab project %s", key, schedule, pid)
+
+	_, err := doRequest(client, "DELETE", fmt.Sprintf("projects/%s/pipeline_schedules/%d/variables/%s", pathEscape(pid), schedule, pathEscape(key)), nil, nil, options...)
+	return err
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// Pipeline triggers hold the tokens which allow to run the pipeline of a
+// project through the API, e.g. from the pipeline of another project; API v3
+// identifies them by their token, and only v4 allows to change them, so the
+// functions in this file perform the requests themselves, and fail on v3.
+
+// pipelineTrigger is a pipeline trigger of a project.
+type pipelineTrigger struct {
+	ID          int    `json:"id"`
+	Description string `json:"description"`
+	Token       string `json:"token"`
+}
+
+// pipelineTriggerOptions represents the available options when creating or
+// editing a pipeline trigger.
+type pipelineTriggerOptions struct {
+	Description *string `url:"description,omitempty" json:"description,omitempty"`
+}
+
+func getPipelineTrigger(client *Client, pid string, trigger int, options ...gitlab.OptionFunc) (*pipelineTrigger, *gitlab.Response, error) {
+	if !client.isV4() {
+		return nil, nil, fmt.Errorf("Pipeline triggers require gitlab API v4")
+	}
+
+	t := &pipelineTrigger{}
+	response, err := doRequest(client, "GET", fmt.Sprintf("projects/%s/triggers/%d", pathEscape(pid), trigger), nil, t, options...)
+	if err != nil {
+		return nil, response, err
+	}
+	return t, response, nil
+}
+
+func createPipelineTrigger(client *Client, pid string, opt *pipelineTriggerOptions, options ...gitlab.OptionFunc) (*pipelineTrigger, error) {
+	if !client.isV4() {
+		return nil, fmt.Errorf("Pipeline triggers require gitlab API v4")
+	}
+
+	log.Printf("[DEBUG] create pipeline trigger %q in gitlab project %s", *opt.Description, pid)
+
+	t := &pipelineTrigger{}
+	if _, err := doRequest(client, "POST", fmt.Sprintf("projects/%s/triggers", pathEscape(pid)), opt, t, options...); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func editPipelineTrigger(client *Client, pid string, trigger int, opt *pipelineTriggerOptions, options ...gitlab.OptionFunc) error {
+	if !client.isV4() {
+		return fmt.Errorf("Pipeline triggers require gitlab API v4")
+	}
+
+	log.Printf("[DEBUG] update pipeline trigger %d of gitlab project %s", trigger, pid)
+
+	_, err := doRequest(client, "PUT", fmt.Sprintf("projects/%s/triggers/%d", pathEscape(pid), trigger), opt, nil, options...)
+	return err
+}
+
+func deletePipelineTrigger(client *Client, pid string, trigger int, options ...gitlab.OptionFunc) error {
+	log.Printf("[DEBUG] delete pipeline trigger %d of gitlab project %s", trigger, pid)
+
+	_, err := doRequest(client, "DELETE", fmt.Sprintf("projects/%s/triggers/%d", pathEscape(pid), trigger), nil, nil, options...)
+	return err
+}
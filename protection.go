@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// Branches and tags are protected through the protected_branches and
+// protected_tags APIs of API v4, where each action (pushing, merging,
+// unprotecting or creating a tag) is allowed to an access level and,
+// where supported, to specific users and groups; none of this is part of the
+// go-gitlab client, so the requests are performed here.
+
+// the names of the access levels of protected branches and tags
+var protectionAccessLevelNames = map[int]string{
+	0:  "no one",
+	30: "developer",
+	40: "master",
+	60: "admin",
+}
+
+var protectionAccessLevels = []string{"no one", "developer", "master", "admin"}
+
+// protectionAccessLevel returns the value of the protection access level with
+// the given name.
+func protectionAccessLevel(name string) int {
+	for value, n := range protectionAccessLevelNames {
+		if n == name {
+			return value
+		}
+	}
+	return 0
+}
+
+// accessLevelAllowance is an access level, user or group allowed to perform
+// an action on a protected branch or tag; only one of the fields is set.
+type accessLevelAllowance struct {
+	AccessLevel            int    `json:"access_level"`
+	AccessLevelDescription string `json:"access_level_description,omitempty"`
+	UserID                 int    `json:"user_id,omitempty"`
+	GroupID                int    `json:"group_id,omitempty"`
+}
+
+// allowanceOptions is a user or group allowed to perform an action, as given
+// when protecting a branch.
+type allowanceOptions struct {
+	UserID  *int `url:"user_id,omitempty" json:"user_id,omitempty"`
+	GroupID *int `url:"group_id,omitempty" json:"group_id,omitempty"`
+}
+
+// protectedBranch is a protected branch, or a wildcard matching a set of
+// branches (e.g. "release/*").
+type protectedBranch struct {
+	Name                  string                  `json:"name"`
+	PushAccessLevels      []*accessLevelAllowance `json:"push_access_levels"`
+	MergeAccessLevels     []*accessLevelAllowance `json:"merge_access_levels"`
+	UnprotectAccessLevels []*accessLevelAllowance `json:"unprotect_access_levels"`
+}
+
+// protectBranchOptions represents the available options when protecting a
+// branch.
+type protectBranchOptions struct {
+	Name                 *string             `url:"name,omitempty" json:"name,omitempty"`
+	PushAccessLevel      *int                `url:"push_access_level,omitempty" json:"push_access_level,omitempty"`
+	MergeAccessLevel     *int                `url:"merge_access_level,omitempty" json:"merge_access_level,omitempty"`
+	UnprotectAccessLevel *int                `url:"unprotect_access_level,omitempty" json:"unprotect_access_level,omitempty"`
+	AllowedToPush        []*allowanceOptions `url:"allowed_to_push,omitempty" json:"allowed_to_push,omitempty"`
+	AllowedToMerge       []*allowanceOptions `url:"allowed_to_merge,omitempty" json:"allowed_to_merge,omitempty"`
+	AllowedToUnprotect   []*allowanceOptions `url:"allowed_to_unprotect,omitempty" json:"allowed_to_unprotect,omitempty"`
+}
+
+func getProtectedBranch(client *Client, pid, branch string, options ...gitlab.OptionFunc) (*protectedBranch, *gitlab.Response, error) {
+	if !client.isV4() {
+		return nil, nil, fmt.Errorf("Protected branches require gitlab API v4")
+	}
+
+	protected := &protectedBranch{}
+	response, err := doRequest(client, "GET", fmt.Sprintf("projects/%s/protected_branches/%s", pathEscape(pid), pathEscape(branch)), nil, protected, options...)
+	if err != nil {
+		return nil, response, err
+	}
+	return protected, response, nil
+}
+
+func protectBranch(client *Client, pid string, opt *protectBranchOptions, options ...gitlab.OptionFunc) error {
+	if !client.isV4() {
+		return fmt.Errorf("Protected branches require gitlab API v4")
+	}
+
+	log.Printf("[DEBUG] protect branch %s of gitlab project %s", *opt.Name, pid)
+
+	_, err := doRequest(client, "POST", fmt.Sprintf("projects/%s/protected_branches", pathEscape(pid)), opt, nil, options...)
+	return err
+}
+
+func unprotectBranch(client *Client, pid, branch string, options ...gitlab.OptionFunc) error {
+	log.Printf("[DEBUG] unprotect branch %s of gitlab project %s", branch, pid)
+
+	_, err := doRequest(client, "DELETE", fmt.Sprintf("projects/%s/protected_branches/%s", pathEscape(pid), pathEscape(branch)), nil, nil, options...)
+	return err
+}
+
+// protectedTag is a protected tag, or a wildcard matching a set of tags (e.g.
+// "v*").
+type protectedTag struct {
+	Name               string                  `json:"name"`
+	CreateAccessLevels []*accessLevelAllowance `json:"create_access_levels"`
+}
+
+// protectTagOptions represents the available options when protecting a tag.
+type protectTagOptions struct {
+	Name              *string `url:"name,omitempty" json:"name,omitempty"`
+	CreateAccessLevel *int    `url:"create_access_level,omitempty" json:"create_access_level,omitempty"`
+}
+
+func getProtectedTag(client *Client, pid, tag string, options ...gitlab.OptionFunc) (*protectedTag, *gitlab.Response, error) {
+	if !client.isV4() {
+		return nil, nil, fmt.Errorf("Protected tags require gitlab API v4")
+	}
+
+	protected := &protectedTag{}
+	response, err := doRequest(client, "GET", fmt.Sprintf("projects/%s/protected_tags/%s", pathEscape(pid), pathEscape(tag)), nil, protected, options...)
+	if err != nil {
+		return nil, response, err
+	}
+	return protected, response, nil
+}
+
+func protectTag(client *Client, pid string, opt *protectTagOptions, options ...gitlab.OptionFunc) error {
+	if !client.isV4() {
+		return fmt.Errorf("Protected tags require gitlab API v4")
+	}
+
+	log.Printf("[DEBUG] protect tag %s of gitlab project %s", *opt.Name, pid)
+
+	_, err := doRequest(client, "POST", fmt.Sprintf("projects/%s/protected_tags", pathEscape(pid)), opt, nil, options...)
+	return err
+}
+
+func unprotectTag(client *Client, pid, tag string, options ...gitlab.OptionFunc) error {
+	log.Printf("[DEBUG] unprotect tag %s of gitlab project %s", tag, pid)
+
+	_, err := doRequest(client, "DELETE", fmt.Sprintf("projects/%s/protected_tags/%s", pathEscape(pid), pathEscape(tag)), nil, nil, options...)
+	return err
+}
+
+// allowedSchema returns the schema of the users and groups allowed to perform
+// an action on a protected branch or tag; each element allows either a user
+// or a group, as gitlab stores them as separate allowances.
+func allowedSchema(action string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Description: fmt.Sprintf("The users and groups allowed to %s, besides the access level; each element sets exactly one of user_id and group_id.", action),
+		Optional:    true,
+		ForceNew:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"user_id": {
+					Type:     schema.TypeInt,
+					Optional: true,
+				},
+				"group_id": {
+					Type:     schema.TypeInt,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+// expandAllowed returns the users and groups in the given set of users and
+// groups allowed to perform an action, each of which must set exactly one of
+// user_id and group_id.
+func expandAllowed(action string, set *schema.Set) ([]*allowanceOptions, error) {
+	allowed := []*allowanceOptions{}
+	for _, v := range set.List() {
+		m := v.(map[string]interface{})
+		user, group := m["user_id"].(int), m["group_id"].(int)
+		switch {
+		case user != 0 && group != 0:
+			return nil, fmt.Errorf("%s: user_id %d and group_id %d cannot be set in the same element", action, user, group)
+		case user != 0:
+			allowed = append(allowed, &allowanceOptions{UserID: gitlab.Int(user)})
+		case group != 0:
+			allowed = append(allowed, &allowanceOptions{GroupID: gitlab.Int(group)})
+		default:
+			return nil, fmt.Errorf("%s: either user_id or group_id must be set", action)
+		}
+	}
+	return allowed, nil
+}
+
+// flattenAllowances splits the allowances of an action into the access
+// level, by name, and the set of allowed users and groups.
+func flattenAllowances(allowances []*accessLevelAllowance) (string, []interface{}) {
+	level := ""
+	allowed := []interface{}{}
+	for _, allowance := range allowances {
+		switch {
+		case allowance.UserID != 0:
+			allowed = append(allowed, map[string]interface{}{"user_id": allowance.UserID, "group_id": 0})
+		case allowance.GroupID != 0:
+			allowed = append(allowed, map[string]interface{}{"user_id": 0, "group_id": allowance.GroupID})
+		default:
+			level = protectionAccessLevelNames[allowance.AccessLevel]
+		}
+	}
+	return level, allowed
+}
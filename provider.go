@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/hashicorp/terraform/terraform"
 )
 
@@ -11,24 +12,128 @@ func Provider() terraform.ResourceProvider {
 	// The actual provider
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
+			"auth_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("GITLAB_AUTH_TYPE", ""),
+				Description:  descriptions["auth_type"],
+				ValidateFunc: validateValueFunc(authTypes),
+			},
 			"token": {
 				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc("GITLAB_TOKEN", nil),
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GITLAB_TOKEN", ""),
 				Description: descriptions["token"],
 				Sensitive:   true,
 			},
+			"oauth_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GITLAB_OAUTH_TOKEN", ""),
+				Description: descriptions["oauth_token"],
+				Sensitive:   true,
+			},
+			"job_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CI_JOB_TOKEN", ""),
+				Description: descriptions["job_token"],
+				Sensitive:   true,
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GITLAB_USERNAME", ""),
+				Description: descriptions["username"],
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GITLAB_PASSWORD", ""),
+				Description: descriptions["password"],
+				Sensitive:   true,
+			},
+			"sudo": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GITLAB_SUDO", ""),
+				Description: descriptions["sudo"],
+			},
 			"base_url": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("GITLAB_BASE_URL", ""),
 				Description: descriptions["base_url"],
 			},
+			"api_version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("GITLAB_API_VERSION", ""),
+				Description:  descriptions["api_version"],
+				ValidateFunc: validateValueFunc([]string{apiV3, apiV4}),
+			},
+			"cacert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GITLAB_CACERT_FILE", ""),
+				Description: descriptions["cacert_file"],
+			},
+			"client_cert": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GITLAB_CLIENT_CERT", ""),
+				Description: descriptions["client_cert"],
+			},
+			"client_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GITLAB_CLIENT_KEY", ""),
+				Description: descriptions["client_key"],
+			},
+			"insecure": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GITLAB_INSECURE", false),
+				Description: descriptions["insecure"],
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("GITLAB_MAX_RETRIES", 3),
+				Description:  descriptions["max_retries"],
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"requests_per_second": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("GITLAB_REQUESTS_PER_SECOND", 0),
+				Description:  descriptions["requests_per_second"],
+				ValidateFunc: validation.IntAtLeast(0),
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"gitlabx_group":        resourceGitlabGroup(),
-			"gitlabx_project":      resourceGitlabProject(),
-			"gitlabx_project_hook": resourceGitlabProjectHook(),
+			"gitlabx_branch":              resourceGitlabBranch(),
+			"gitlabx_branch_protection":   resourceGitlabBranchProtection(),
+			"gitlabx_deploy_key":          resourceGitlabDeployKey(),
+			"gitlabx_deploy_key_enable":   resourceGitlabDeployKeyEnable(),
+			"gitlabx_deploy_token":        resourceGitlabDeployToken(),
+			"gitlabx_group":               resourceGitlabGroup(),
+			"gitlabx_group_members":       resourceGitlabGroupMembers(),
+			"gitlabx_group_membership":    resourceGitlabGroupMembership(),
+			"gitlabx_group_share_group":   resourceGitlabGroupShareGroup(),
+			"gitlabx_group_variable":      resourceGitlabGroupVariable(),
+			"gitlabx_pipeline_schedule":   resourceGitlabPipelineSchedule(),
+			"gitlabx_pipeline_trigger":    resourceGitlabPipelineTrigger(),
+			"gitlabx_project":             resourceGitlabProject(),
+			"gitlabx_project_hook":        resourceGitlabProjectHook(),
+			"gitlabx_project_members":     resourceGitlabProjectMembers(),
+			"gitlabx_project_membership":  resourceGitlabProjectMembership(),
+			"gitlabx_project_share_group": resourceGitlabProjectShareGroup(),
+			"gitlabx_project_variable":    resourceGitlabProjectVariable(),
+			"gitlabx_project_variables":   resourceGitlabProjectVariables(),
+			"gitlabx_repository_commit":   resourceGitlabRepositoryCommit(),
+			"gitlabx_repository_file":     resourceGitlabRepositoryFile(),
+			"gitlabx_tag_protection":      resourceGitlabTagProtection(),
 		},
 
 		ConfigureFunc: providerConfigure,
@@ -39,15 +144,42 @@ var descriptions map[string]string
 
 func init() {
 	descriptions = map[string]string{
-		"token":    "The OAuth token used to connect to GitLab.",
-		"base_url": "The GitLab Base API URL.",
+		"auth_type":           "How to authenticate: private_token, oauth_token, job_token or password; inferred from the credentials by default.",
+		"token":               "The private token used to connect to GitLab.",
+		"oauth_token":         "The OAuth2 access token used to connect to GitLab.",
+		"job_token":           "The CI job token used to connect to GitLab.",
+		"username":            "The username used to obtain an OAuth2 token from GitLab.",
+		"password":            "The password used to obtain an OAuth2 token from GitLab.",
+		"sudo":                "The user (ID or username) to act as; requires an admin token. Only gitlabx_group and gitlabx_project can override it, with their own sudo argument.",
+		"base_url":            "The GitLab Base API URL.",
+		"api_version":         "The GitLab API version (v3 or v4); detected automatically by default.",
+		"cacert_file":         "A file containing the CA certificate(s) to trust when connecting to GitLab.",
+		"client_cert":         "A file containing the client certificate to present to GitLab.",
+		"client_key":          "A file containing the private key of the client certificate.",
+		"insecure":            "Whether to skip the verification of the GitLab server certificate.",
+		"max_retries":         "How many times a request failing with a transient error is retried.",
+		"requests_per_second": "The maximum number of requests per second sent to GitLab; 0 means no limit.",
 	}
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	config := Config{
-		Token:   d.Get("token").(string),
-		BaseURL: d.Get("base_url").(string),
+		AuthType:   d.Get("auth_type").(string),
+		Token:      d.Get("token").(string),
+		OAuthToken: d.Get("oauth_token").(string),
+		JobToken:   d.Get("job_token").(string),
+		Username:   d.Get("username").(string),
+		Password:   d.Get("password").(string),
+		Sudo:       d.Get("sudo").(string),
+		BaseURL:    d.Get("base_url").(string),
+		APIVersion: d.Get("api_version").(string),
+		CACertFile: d.Get("cacert_file").(string),
+		ClientCert: d.Get("client_cert").(string),
+		ClientKey:  d.Get("client_key").(string),
+		Insecure:   d.Get("insecure").(bool),
+
+		MaxRetries:        d.Get("max_retries").(int),
+		RequestsPerSecond: d.Get("requests_per_second").(int),
 	}
 
 	return config.Client()
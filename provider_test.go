@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	"strconv"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/schema"
@@ -36,3 +37,22 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("GITLAB_BASE_URL must be set for acceptance tests")
 	}
 }
+
+// testAccUserID returns the ID of an existing user, other than the one the
+// acceptance tests authenticate as, to be used in the tests of memberships;
+// such tests are skipped unless GITLAB_TEST_USER_ID is set.
+func testAccUserID(t *testing.T) int {
+	v := os.Getenv("GITLAB_TEST_USER_ID")
+	if v == "" {
+		t.Skip("GITLAB_TEST_USER_ID must be set for acceptance tests involving other users")
+	}
+	id, err := strconv.Atoi(v)
+	if err != nil {
+		t.Fatalf("GITLAB_TEST_USER_ID must be a numeric user ID: %s", err)
+	}
+	return id
+}
+
+// testSSHKey is a valid SSH public key (whose private key has been thrown
+// away) for the tests of deploy keys.
+const testSSHKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIKxfVO9AJYHySh6ZkeY7hXYlGvaDf8g8DPot3raS47UZ deploy@example.com"
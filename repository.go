@@ -0,0 +1,215 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"log"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// The repository files and commits APIs changed completely between API v3 and
+// v4 (e.g. files are addressed by their path rather than through query
+// parameters, and commits can change several files at once), and go-gitlab
+// only knows about the former; the functions in this file use the v4 API
+// directly, and fail on v3.
+
+// repositoryFile is a file in a repository, as returned by the files API.
+type repositoryFile struct {
+	FileName      string `json:"file_name"`
+	FilePath      string `json:"file_path"`
+	Size          int    `json:"size"`
+	Encoding      string `json:"encoding"`
+	Content       string `json:"content"`
+	ContentSHA256 string `json:"content_sha256"`
+	Ref           string `json:"ref"`
+	BlobID        string `json:"blob_id"`
+	CommitID      string `json:"commit_id"`
+	LastCommitID  string `json:"last_commit_id"`
+}
+
+// decodedContent returns the content of the file, decoded.
+func (f *repositoryFile) decodedContent() (string, error) {
+	if f.Encoding != "base64" {
+		return f.Content, nil
+	}
+	content, err := base64.StdEncoding.DecodeString(f.Content)
+	if err != nil {
+		return "", fmt.Errorf("Error decoding the content of %s: %s", f.FilePath, err)
+	}
+	return string(content), nil
+}
+
+// contentSHA256 returns the SHA-256 hash of the given content, in the same
+// format as the files API.
+func contentSHA256(content string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(content)))
+}
+
+// getFileOptions represents the available options when retrieving a file.
+type getFileOptions struct {
+	Ref *string `url:"ref,omitempty" json:"ref,omitempty"`
+}
+
+// fileOptions represents the available options when creating, updating or
+// deleting a file; the content is always sent base64-encoded, so that any
+// content makes it through unchanged.
+type fileOptions struct {
+	Branch        *string `url:"branch,omitempty" json:"branch,omitempty"`
+	Encoding      *string `url:"encoding,omitempty" json:"encoding,omitempty"`
+	Content       *string `url:"content,omitempty" json:"content,omitempty"`
+	CommitMessage *string `url:"commit_message,omitempty" json:"commit_message,omitempty"`
+	AuthorEmail   *string `url:"author_email,omitempty" json:"author_email,omitempty"`
+	AuthorName    *string `url:"author_name,omitempty" json:"author_name,omitempty"`
+}
+
+func getFile(client *Client, pid, path, ref string, options ...gitlab.OptionFunc) (*repositoryFile, *gitlab.Response, error) {
+	if !client.isV4() {
+		return nil, nil, fmt.Errorf("Repository files require gitlab API v4")
+	}
+
+	file := &repositoryFile{}
+	opt := &getFileOptions{Ref: gitlab.String(ref)}
+	response, err := doRequest(client, "GET", fmt.Sprintf("projects/%s/repository/files/%s", pathEscape(pid), pathEscape(path)), opt, file, options...)
+	if err != nil {
+		return nil, response, err
+	}
+	return file, response, nil
+}
+
+// writeFile creates ("POST"), updates ("PUT") or deletes ("DELETE") a file
+// in a new commit.
+func writeFile(client *Client, method, pid, path string, opt *fileOptions, options ...gitlab.OptionFunc) error {
+	if !client.isV4() {
+		return fmt.Errorf("Repository files require gitlab API v4")
+	}
+	if opt.Content != nil {
+		opt.Encoding = gitlab.String("base64")
+		opt.Content = gitlab.String(base64.StdEncoding.EncodeToString([]byte(*opt.Content)))
+	}
+
+	log.Printf("[DEBUG] %s file %s in branch %s of gitlab project %s", method, path, *opt.Branch, pid)
+
+	_, err := doRequest(client, method, fmt.Sprintf("projects/%s/repository/files/%s", pathEscape(pid), pathEscape(path)), opt, nil, options...)
+	return err
+}
+
+// repositoryCommit is a commit in a repository, as returned by the commits
+// API.
+type repositoryCommit struct {
+	ID          string   `json:"id"`
+	ShortID     string   `json:"short_id"`
+	Title       string   `json:"title"`
+	Message     string   `json:"message"`
+	AuthorName  string   `json:"author_name"`
+	AuthorEmail string   `json:"author_email"`
+	ParentIDs   []string `json:"parent_ids"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+// commitAction is a change to a file in a commit: its action is one of
+// "create", "update", "delete" and "move".
+type commitAction struct {
+	Action       *string `url:"action,omitempty" json:"action,omitempty"`
+	FilePath     *string `url:"file_path,omitempty" json:"file_path,omitempty"`
+	PreviousPath *string `url:"previous_path,omitempty" json:"previous_path,omitempty"`
+	Encoding     *string `url:"encoding,omitempty" json:"encoding,omitempty"`
+	Content      *string `url:"content,omitempty" json:"content,omitempty"`
+}
+
+// createCommitOptions represents the available options when creating a
+// commit; as with files, the content of the actions is always sent
+// base64-encoded.
+type createCommitOptions struct {
+	Branch        *string         `url:"branch,omitempty" json:"branch,omitempty"`
+	StartBranch   *string         `url:"start_branch,omitempty" json:"start_branch,omitempty"`
+	CommitMessage *string         `url:"commit_message,omitempty" json:"commit_message,omitempty"`
+	Actions       []*commitAction `url:"actions,omitempty" json:"actions,omitempty"`
+	AuthorEmail   *string         `url:"author_email,omitempty" json:"author_email,omitempty"`
+	AuthorName    *string         `url:"author_name,omitempty" json:"author_name,omitempty"`
+}
+
+func getCommit(client *Client, pid, sha string, options ...gitlab.OptionFunc) (*repositoryCommit, *gitlab.Response, error) {
+	if !client.isV4() {
+		return nil, nil, fmt.Errorf("Repository commits require gitlab API v4")
+	}
+
+	commit := &repositoryCommit{}
+	response, err := doRequest(client, "GET", fmt.Sprintf("projects/%s/repository/commits/%s", pathEscape(pid), pathEscape(sha)), nil, commit, options...)
+	if err != nil {
+		return nil, response, err
+	}
+	return commit, response, nil
+}
+
+// createCommit applies all the actions in the given options to a branch in a
+// single commit.
+func createCommit(client *Client, pid string, opt *createCommitOptions, options ...gitlab.OptionFunc) (*repositoryCommit, error) {
+	if !client.isV4() {
+		return nil, fmt.Errorf("Repository commits require gitlab API v4")
+	}
+	for _, action := range opt.Actions {
+		if action.Content != nil {
+			action.Encoding = gitlab.String("base64")
+			action.Content = gitlab.String(base64.StdEncoding.EncodeToString([]byte(*action.Content)))
+		}
+	}
+
+	log.Printf("[DEBUG] commit %d actions to branch %s of gitlab project %s", len(opt.Actions), *opt.Branch, pid)
+
+	commit := &repositoryCommit{}
+	_, err := doRequest(client, "POST", fmt.Sprintf("projects/%s/repository/commits", pathEscape(pid)), opt, commit, options...)
+	if err != nil {
+		return nil, err
+	}
+	return commit, nil
+}
+
+// repositoryBranch is a branch of a repository, as returned by the branches
+// API.
+type repositoryBranch struct {
+	Name      string            `json:"name"`
+	Merged    bool              `json:"merged"`
+	Protected bool              `json:"protected"`
+	Default   bool              `json:"default"`
+	Commit    *repositoryCommit `json:"commit"`
+}
+
+// createBranchOptions represents the available options when creating a
+// branch.
+type createBranchOptions struct {
+	Branch *string `url:"branch,omitempty" json:"branch,omitempty"`
+	Ref    *string `url:"ref,omitempty" json:"ref,omitempty"`
+}
+
+func getBranch(client *Client, pid, branch string, options ...gitlab.OptionFunc) (*repositoryBranch, *gitlab.Response, error) {
+	if !client.isV4() {
+		return nil, nil, fmt.Errorf("Repository branches require gitlab API v4")
+	}
+
+	b := &repositoryBranch{}
+	response, err := doRequest(client, "GET", fmt.Sprintf("projects/%s/repository/branches/%s", pathEscape(pid), pathEscape(branch)), nil, b, options...)
+	if err != nil {
+		return nil, response, err
+	}
+	return b, response, nil
+}
+
+func createBranch(client *Client, pid string, opt *createBranchOptions, options ...gitlab.OptionFunc) error {
+	if !client.isV4() {
+		return fmt.Errorf("Repository branches require gitlab API v4")
+	}
+
+	log.Printf("[DEBUG] create branch %s from %s in gitlab project %s", *opt.Branch, *opt.Ref, pid)
+
+	_, err := doRequest(client, "POST", fmt.Sprintf("projects/%s/repository/branches", pathEscape(pid)), opt, nil, options...)
+	return err
+}
+
+func deleteBranch(client *Client, pid, branch string, options ...gitlab.OptionFunc) error {
+	log.Printf("[DEBUG] delete branch %s of gitlab project %s", branch, pid)
+
+	_, err := doRequest(client, "DELETE", fmt.Sprintf("projects/%s/repository/branches/%s", pathEscape(pid), pathEscape(branch)), nil, nil, options...)
+	return err
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func resourceGitlabBranch() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGitlabBranchCreate,
+		Read:   resourceGitlabBranchRead,
+		Update: resourceGitlabBranchUpdate,
+		Delete: resourceGitlabBranchDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabBranchImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Description: "The ID or path with namespace of the project.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// the branch moves on as commits are pushed to it, so the ref it
+			// was created from is never read back from gitlab, and is unknown
+			// for imported branches
+			"ref": {
+				Type:        schema.TypeString,
+				Description: "The branch name or commit SHA to create the branch from.",
+				Required:    true,
+				ForceNew:    true,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return d.Id() != "" && old == ""
+				},
+			},
+			"delete_on_destroy": {
+				Type:        schema.TypeBool,
+				Description: "Whether the branch is deleted when the resource is destroyed; if not, it is only removed from the state.",
+				Optional:    true,
+				Default:     true,
+			},
+			"commit_sha": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"protected": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// branches are imported by a composite "<project>:<name>" ID, where the
+// project can be either its numeric ID or its path with namespace.
+func resourceGitlabBranchImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	project, name, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[DEBUG] import gitlab branch %s", d.Id())
+
+	d.Set("project", project)
+	d.Set("name", name)
+	d.Set("delete_on_destroy", true)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGitlabBranchCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	name := d.Get("name").(string)
+
+	options := &createBranchOptions{
+		Branch: gitlab.String(name),
+		Ref:    gitlab.String(d.Get("ref").(string)),
+	}
+
+	if err := createBranch(client, project, options); err != nil {
+		return fmt.Errorf("Error creating branch %s in project %s: %s", name, project, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", project, name))
+
+	return resourceGitlabBranchRead(d, meta)
+}
+
+func resourceGitlabBranchRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	name := d.Get("name").(string)
+	log.Printf("[DEBUG] read gitlab branch %s", d.Id())
+
+	branch, response, err := getBranch(client, project, name)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing branch %s from state because it no longer exists in gitlab", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	if branch.Commit != nil {
+		d.Set("commit_sha", branch.Commit.ID)
+	}
+	d.Set("protected", branch.Protected)
+	return nil
+}
+
+// only delete_on_destroy can be updated, and it only lives in the state
+func resourceGitlabBranchUpdate(d *schema.ResourceData, meta interface{}) error {
+	return resourceGitlabBranchRead(d, meta)
+}
+
+func resourceGitlabBranchDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	name := d.Get("name").(string)
+
+	if !d.Get("delete_on_destroy").(bool) {
+		log.Printf("[DEBUG] keeping gitlab branch %s, removing it from state only", d.Id())
+		return nil
+	}
+
+	return deleteBranch(client, project, name)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func resourceGitlabBranchProtection() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGitlabBranchProtectionCreate,
+		Read:   resourceGitlabBranchProtectionRead,
+		Delete: resourceGitlabBranchProtectionDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabBranchProtectionImport,
+		},
+
+		// protected branches cannot be updated, only unprotected and
+		// protected again
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Description: "The ID or path with namespace of the project.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"branch": {
+				Type:        schema.TypeString,
+				Description: "The name of the branch, or a wildcard matching several branches (e.g. \"release/*\").",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"push_access_level": {
+				Type:         schema.TypeString,
+				Description:  "Who can push to the branch: one of no one, developer, master or admin.",
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "master",
+				ValidateFunc: validateValueFunc(protectionAccessLevels),
+			},
+			"merge_access_level": {
+				Type:         schema.TypeString,
+				Description:  "Who can merge into the branch: one of no one, developer, master or admin.",
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "master",
+				ValidateFunc: validateValueFunc(protectionAccessLevels),
+			},
+			"unprotect_access_level": {
+				Type:         schema.TypeString,
+				Description:  "Who can unprotect the branch: one of developer, master or admin; by default, masters.",
+				Optional:     true,
+				ForceNew:     true,
+				Computed:     true,
+				ValidateFunc: validateValueFunc([]string{"developer", "master", "admin"}),
+			},
+			// the following are only supported by GitLab Enterprise Edition
+			"allowed_to_push":      allowedSchema("push"),
+			"allowed_to_merge":     allowedSchema("merge"),
+			"allowed_to_unprotect": allowedSchema("unprotect"),
+		},
+	}
+}
+
+// protected branches are imported by a composite "<project>:<branch>" ID,
+// where the project can be either its numeric ID or its path with namespace.
+func resourceGitlabBranchProtectionImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	project, branch, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[DEBUG] import gitlab protected branch %s/%s", project, branch)
+
+	d.Set("project", project)
+	d.Set("branch", branch)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGitlabBranchProtectionCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	branch := d.Get("branch").(string)
+	options := &protectBranchOptions{
+		Name:             gitlab.String(branch),
+		PushAccessLevel:  gitlab.Int(protectionAccessLevel(d.Get("push_access_level").(string))),
+		MergeAccessLevel: gitlab.Int(protectionAccessLevel(d.Get("merge_access_level").(string))),
+	}
+
+	var err error
+	if options.AllowedToPush, err = expandAllowed("allowed_to_push", d.Get("allowed_to_push").(*schema.Set)); err != nil {
+		return err
+	}
+	if options.AllowedToMerge, err = expandAllowed("allowed_to_merge", d.Get("allowed_to_merge").(*schema.Set)); err != nil {
+		return err
+	}
+	if options.AllowedToUnprotect, err = expandAllowed("allowed_to_unprotect", d.Get("allowed_to_unprotect").(*schema.Set)); err != nil {
+		return err
+	}
+
+	if v, ok := d.GetOk("unprotect_access_level"); ok {
+		options.UnprotectAccessLevel = gitlab.Int(protectionAccessLevel(v.(string)))
+	}
+
+	if err := protectBranch(client, project, options); err != nil {
+		return fmt.Errorf("Error protecting branch %s of project %s: %s", branch, project, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", project, branch))
+
+	return resourceGitlabBranchProtectionRead(d, meta)
+}
+
+func resourceGitlabBranchProtectionRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	branch := d.Get("branch").(string)
+	log.Printf("[DEBUG] read gitlab protected branch %s", d.Id())
+
+	protected, response, err := getProtectedBranch(client, project, branch)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing protected branch %s from state because it no longer exists in gitlab", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	level, allowed := flattenAllowances(protected.PushAccessLevels)
+	d.Set("push_access_level", level)
+	d.Set("allowed_to_push", allowed)
+
+	level, allowed = flattenAllowances(protected.MergeAccessLevels)
+	d.Set("merge_access_level", level)
+	d.Set("allowed_to_merge", allowed)
+
+	level, allowed = flattenAllowances(protected.UnprotectAccessLevels)
+	d.Set("unprotect_access_level", level)
+	d.Set("allowed_to_unprotect", allowed)
+	return nil
+}
+
+func resourceGitlabBranchProtectionDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	branch := d.Get("branch").(string)
+
+	return unprotectBranch(client, project, branch)
+}
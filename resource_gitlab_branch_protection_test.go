@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGitlabBranchProtection_basic(t *testing.T) {
+	var protected protectedBranch
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabBranchProtectionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabBranchProtectionConfig(rInt, "master", "master", "developer"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabBranchProtectionExists("gitlabx_branch_protection.foo", &protected),
+					testAccCheckGitlabBranchProtectionAttributes(&protected, 40, 30),
+				),
+			},
+			// Protect the branches again with different access levels
+			{
+				Config: testAccGitlabBranchProtectionConfig(rInt, "release/*", "no one", "master"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabBranchProtectionExists("gitlabx_branch_protection.foo", &protected),
+					testAccCheckGitlabBranchProtectionAttributes(&protected, 0, 40),
+				),
+			},
+			{
+				ResourceName:      "gitlabx_branch_protection.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGitlabBranchProtection_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+	user := f.addUser("pusher")
+
+	protected := func() string {
+		return fmt.Sprintf("%s/protected_branches/%s", f.find("projects", "path", "foo-1"), url.PathEscape("release/*"))
+	}
+	config := testFakeGitlabConfig(f) + testAccGitlabBranchProtectionConfig(1, "release/*", "no one", "developer") + fmt.Sprintf(`
+resource "gitlabx_branch_protection" "allowed" {
+  project = "${gitlabx_project.foo.id}"
+  branch = "master"
+  push_access_level = "master"
+  unprotect_access_level = "admin"
+
+  allowed_to_push {
+    user_id = %d
+  }
+}
+	`, user)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "projects", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlabx_branch_protection.foo", "unprotect_access_level", "master"),
+					resource.TestCheckResourceAttr("gitlabx_branch_protection.allowed", "allowed_to_push.#", "1"),
+					func(s *terraform.State) error {
+						object := f.get(protected())
+						if object == nil {
+							return fmt.Errorf("the branches have not been protected")
+						}
+						push := object["push_access_levels"].([]fakeObject)
+						merge := object["merge_access_levels"].([]fakeObject)
+						if len(push) != 1 || push[0]["access_level"] != 0 || merge[0]["access_level"] != 30 {
+							return fmt.Errorf("got push %v and merge %v; want no one and developers", push, merge)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "gitlabx_branch_protection.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "gitlabx_branch_protection.allowed",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Protect the branches again after their access levels have been
+			// changed outside of Terraform
+			{
+				PreConfig: func() {
+					f.set(protected(), fakeObject{
+						"push_access_levels": []fakeObject{{"access_level": 40}},
+					})
+				},
+				Config: config,
+				Check: func(s *terraform.State) error {
+					if push := f.get(protected())["push_access_levels"].([]fakeObject); push[0]["access_level"] != 0 {
+						return fmt.Errorf("got push %v; want no one", push)
+					}
+					return nil
+				},
+			},
+			// Protect the branches again after they have been unprotected
+			// outside of Terraform
+			{
+				PreConfig: func() {
+					f.remove(protected())
+				},
+				Config: config,
+				Check: func(s *terraform.State) error {
+					if f.get(protected()) == nil {
+						return fmt.Errorf("the branches have not been protected")
+					}
+					return nil
+				},
+			},
+			{
+				Config:      config + testFakeGitlabBranchProtectionAllowedConfig(fmt.Sprintf("user_id = %d\n    group_id = 1", user)),
+				ExpectError: regexp.MustCompile("allowed_to_merge: user_id [0-9]+ and group_id 1 cannot be set in the same element"),
+			},
+			{
+				Config:      config + testFakeGitlabBranchProtectionAllowedConfig(""),
+				ExpectError: regexp.MustCompile("allowed_to_merge: either user_id or group_id must be set"),
+			},
+		},
+	})
+}
+
+// testFakeGitlabBranchProtectionAllowedConfig protects the develop branch,
+// allowing to merge as set by the given allowed_to_merge element.
+func testFakeGitlabBranchProtectionAllowedConfig(allowed string) string {
+	return fmt.Sprintf(`
+resource "gitlabx_branch_protection" "invalid" {
+  project = "${gitlabx_project.foo.id}"
+  branch = "develop"
+
+  allowed_to_merge {
+    %s
+  }
+}
+	`, allowed)
+}
+
+func testAccCheckGitlabBranchProtectionExists(n string, protected *protectedBranch) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*Client)
+
+		got, _, err := getProtectedBranch(conn, rs.Primary.Attributes["project"], rs.Primary.Attributes["branch"])
+		if err != nil {
+			return err
+		}
+		*protected = *got
+		return nil
+	}
+}
+
+func testAccCheckGitlabBranchProtectionAttributes(protected *protectedBranch, push, merge int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if len(protected.PushAccessLevels) == 0 || protected.PushAccessLevels[0].AccessLevel != push {
+			return fmt.Errorf("got push access levels %v; want %d", protected.PushAccessLevels, push)
+		}
+		if len(protected.MergeAccessLevels) == 0 || protected.MergeAccessLevels[0].AccessLevel != merge {
+			return fmt.Errorf("got merge access levels %v; want %d", protected.MergeAccessLevels, merge)
+		}
+		return nil
+	}
+}
+
+func testAccCheckGitlabBranchProtectionDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gitlabx_branch_protection" {
+			continue
+		}
+
+		_, resp, err := getProtectedBranch(conn, rs.Primary.Attributes["project"], rs.Primary.Attributes["branch"])
+		if err == nil {
+			return fmt.Errorf("Protected branch %s still exists", rs.Primary.ID)
+		}
+		if resp == nil || resp.StatusCode != 404 {
+			return err
+		}
+	}
+	return nil
+}
+
+func testAccGitlabBranchProtectionConfig(rInt int, branch, push, merge string) string {
+	return fmt.Sprintf(`
+resource "gitlabx_project" "foo" {
+  name = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_branch_protection" "foo" {
+  project = "${gitlabx_project.foo.id}"
+  branch = "%s"
+  push_access_level = "%s"
+  merge_access_level = "%s"
+}
+	`, rInt, branch, push, merge)
+}
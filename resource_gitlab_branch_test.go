@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGitlabBranch_basic(t *testing.T) {
+	var branch repositoryBranch
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabBranchDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabBranchConfig(rInt) + testAccGitlabBranchesConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabBranchExists("gitlabx_branch.develop", &branch),
+					testAccCheckGitlabBranchExists("gitlabx_branch.release", &branch),
+					resource.TestCheckResourceAttr("gitlabx_branch.develop", "protected", "false"),
+					resource.TestCheckResourceAttr("gitlabx_branch.release", "protected", "true"),
+				),
+			},
+			{
+				ResourceName:            "gitlabx_branch.develop",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"ref"},
+			},
+		},
+	})
+}
+
+func TestGitlabBranch_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	branch := func(name string) string {
+		return f.branchPath(f.find("projects", "path", "foo-1"), name)
+	}
+	exists := func(name string, want bool) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			if got := f.get(branch(name)) != nil; got != want {
+				return fmt.Errorf("got branch %s existing %t; want %t", name, got, want)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "projects", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabBranchConfig(1) + testAccGitlabBranchesConfig,
+				Check: resource.ComposeTestCheckFunc(
+					exists("develop", true),
+					exists("release/1.0", true),
+					resource.TestCheckResourceAttrSet("gitlabx_branch.develop", "commit_sha"),
+					resource.TestCheckResourceAttr("gitlabx_branch.develop", "protected", "false"),
+					resource.TestCheckResourceAttr("gitlabx_branch.release", "protected", "true"),
+				),
+			},
+			{
+				ResourceName:            "gitlabx_branch.develop",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"ref"},
+			},
+			// Create the branch again after it has been deleted outside of
+			// Terraform
+			{
+				PreConfig: func() {
+					f.remove(branch("develop"))
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabBranchConfig(1) + testAccGitlabBranchesConfig,
+				Check:  exists("develop", true),
+			},
+			// Destroy the branches: release/1.0 is kept
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabBranchConfig(1),
+				Check: resource.ComposeTestCheckFunc(
+					exists("develop", false),
+					exists("release/1.0", true),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabBranchExists(n string, branch *repositoryBranch) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*Client)
+
+		got, _, err := getBranch(conn, rs.Primary.Attributes["project"], rs.Primary.Attributes["name"])
+		if err != nil {
+			return err
+		}
+		*branch = *got
+		return nil
+	}
+}
+
+func testAccCheckGitlabBranchDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gitlabx_branch" || rs.Primary.Attributes["delete_on_destroy"] != "true" {
+			continue
+		}
+
+		_, resp, err := getBranch(conn, rs.Primary.Attributes["project"], rs.Primary.Attributes["name"])
+		if err == nil {
+			return fmt.Errorf("Branch %s still exists", rs.Primary.ID)
+		}
+		if resp == nil || resp.StatusCode != 404 {
+			return err
+		}
+	}
+	return nil
+}
+
+// testAccGitlabBranchConfig returns a project whose master branch has a
+// README, so that other branches can be created from it.
+func testAccGitlabBranchConfig(rInt int) string {
+	return fmt.Sprintf(`
+resource "gitlabx_project" "foo" {
+  name = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_repository_file" "readme" {
+  project = "${gitlabx_project.foo.id}"
+  branch = "master"
+  file_path = "README.md"
+  content = "hello\n"
+}
+
+resource "gitlabx_branch_protection" "release" {
+  project = "${gitlabx_project.foo.id}"
+  branch = "release/*"
+}
+	`, rInt)
+}
+
+const testAccGitlabBranchesConfig = `
+resource "gitlabx_branch" "develop" {
+  project = "${gitlabx_project.foo.id}"
+  name = "develop"
+  ref = "${gitlabx_repository_file.readme.branch}"
+}
+
+resource "gitlabx_branch" "release" {
+  project = "${gitlabx_project.foo.id}"
+  name = "release/1.0"
+  ref = "${gitlabx_branch.develop.name}"
+  delete_on_destroy = false
+
+  depends_on = ["gitlabx_branch_protection.release"]
+}
+`
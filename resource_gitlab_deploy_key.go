@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func resourceGitlabDeployKey() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGitlabDeployKeyCreate,
+		Read:   resourceGitlabDeployKeyRead,
+		Delete: resourceGitlabDeployKeyDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabDeployKeyImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Description: "The ID or path with namespace of the project.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"title": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"key": {
+				Type:         schema.TypeString,
+				Description:  "The SSH public key, in the OpenSSH format.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateSSHKey,
+			},
+			"can_push": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+			"fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// deploy keys are imported by a composite "<project>:<key id>" ID, where the
+// project can be either its numeric ID or its path with namespace.
+func resourceGitlabDeployKeyImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	project, keyId, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := strconv.Atoi(keyId); err != nil {
+		return nil, fmt.Errorf("Invalid deploy key ID %q in import ID %q", keyId, d.Id())
+	}
+
+	log.Printf("[DEBUG] import gitlab deploy key %s/%s", project, keyId)
+
+	d.Set("project", project)
+	d.SetId(keyId)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGitlabDeployKeyCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	title := d.Get("title").(string)
+	options := &addDeployKeyOptions{
+		Title:   gitlab.String(title),
+		Key:     gitlab.String(d.Get("key").(string)),
+		CanPush: gitlab.Bool(d.Get("can_push").(bool)),
+	}
+
+	key, err := addDeployKey(client, project, options)
+	if err != nil {
+		return fmt.Errorf("Error adding deploy key %s to project %s: %s", title, project, err)
+	}
+
+	d.SetId(fmt.Sprintf("%d", key.ID))
+
+	return resourceGitlabDeployKeyRead(d, meta)
+}
+
+func resourceGitlabDeployKeyRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	keyId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] read gitlab deploy key %s/%d", project, keyId)
+
+	key, response, err := getDeployKey(client, project, keyId)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing deploy key %d from state because it no longer exists in gitlab", keyId)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	return setDeployKeyToState(d, key)
+}
+
+func resourceGitlabDeployKeyDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	keyId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	return deleteDeployKey(client, project, keyId)
+}
+
+// setDeployKeyToState sets the attributes of the given deploy key; the key is
+// only replaced if its fingerprint has changed, since gitlab may not give it
+// back exactly as it was added (e.g. without its comment).
+func setDeployKeyToState(d *schema.ResourceData, key *deployKey) error {
+	fingerprint, err := sshKeyFingerprint(key.Key)
+	if err != nil {
+		return fmt.Errorf("Error reading deploy key %d: %s", key.ID, err)
+	}
+	if current, _ := sshKeyFingerprint(d.Get("key").(string)); current != fingerprint {
+		d.Set("key", key.Key)
+	}
+
+	d.Set("title", key.Title)
+	d.Set("can_push", key.CanPush)
+	d.Set("fingerprint", fingerprint)
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// A deploy key can be enabled on projects other than the one it was added to,
+// which then share it; disabling it leaves it in place on the other projects.
+func resourceGitlabDeployKeyEnable() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGitlabDeployKeyEnableCreate,
+		Read:   resourceGitlabDeployKeyEnableRead,
+		Delete: resourceGitlabDeployKeyEnableDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabDeployKeyEnableImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Description: "The ID or path with namespace of the project.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"key_id": {
+				Type:        schema.TypeInt,
+				Description: "The ID of a deploy key added to another project.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"title": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"key": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"can_push": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// enabled deploy keys are imported by a composite "<project>:<key id>" ID,
+// where the project can be either its numeric ID or its path with namespace.
+func resourceGitlabDeployKeyEnableImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	project, keyId, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	key, err := strconv.Atoi(keyId)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid deploy key ID %q in import ID %q", keyId, d.Id())
+	}
+
+	log.Printf("[DEBUG] import gitlab deploy key %s enabled on project %s", keyId, project)
+
+	d.Set("project", project)
+	d.Set("key_id", key)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGitlabDeployKeyEnableCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	keyId := d.Get("key_id").(int)
+
+	if _, err := enableDeployKey(client, project, keyId); err != nil {
+		return fmt.Errorf("Error enabling deploy key %d on project %s: %s", keyId, project, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%d", project, keyId))
+
+	return resourceGitlabDeployKeyEnableRead(d, meta)
+}
+
+func resourceGitlabDeployKeyEnableRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	keyId := d.Get("key_id").(int)
+	log.Printf("[DEBUG] read gitlab deploy key %d enabled on project %s", keyId, project)
+
+	key, response, err := getDeployKey(client, project, keyId)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing enabled deploy key %s from state because it no longer exists in gitlab", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	return setDeployKeyToState(d, key)
+}
+
+func resourceGitlabDeployKeyEnableDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	keyId := d.Get("key_id").(int)
+
+	return deleteDeployKey(client, project, keyId)
+}
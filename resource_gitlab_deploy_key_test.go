@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGitlabDeployKey_basic(t *testing.T) {
+	var key deployKey
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabDeployKeyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabDeployKeyConfig(rInt, testSSHKey, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabDeployKeyExists("gitlabx_deploy_key.foo", &key),
+					testAccCheckGitlabDeployKeyAttributes(&key, fmt.Sprintf("deploy-%d", rInt), true),
+					testAccCheckGitlabDeployKeyExists("gitlabx_deploy_key_enable.bar", &key),
+					testAccCheckGitlabDeployKeyAttributes(&key, fmt.Sprintf("deploy-%d", rInt), false),
+				),
+			},
+			{
+				ResourceName:      "gitlabx_deploy_key_enable.bar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGitlabDeployKey_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	enabled := func(path string) func() string {
+		return func() string {
+			return fmt.Sprintf("%s/deploy_keys/%s", f.find("projects", "path", path), strings.TrimPrefix(f.find("deploy_keys", "title", "deploy-1"), "deploy_keys/"))
+		}
+	}
+	foo, bar := enabled("foo-1"), enabled("bar-1")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testCheckFakeGitlabCount(f, "projects", 0),
+			testCheckFakeGitlabCount(f, "deploy_keys", 0),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabDeployKeyConfig(1, testSSHKey, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlabx_deploy_key.foo", "fingerprint", "6b:a8:d9:61:76:fb:f4:61:74:d4:4a:aa:05:be:e9:14"),
+					resource.TestCheckResourceAttr("gitlabx_deploy_key_enable.bar", "title", "deploy-1"),
+					resource.TestCheckResourceAttr("gitlabx_deploy_key_enable.bar", "can_push", "false"),
+					func(s *terraform.State) error {
+						if f.get(foo())["can_push"] != true {
+							return fmt.Errorf("the deploy key cannot push to foo-1")
+						}
+						if f.get(bar()) == nil {
+							return fmt.Errorf("the deploy key has not been enabled on bar-1")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName: "gitlabx_deploy_key.foo",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["gitlabx_deploy_key.foo"]
+					if !ok {
+						return "", fmt.Errorf("Not Found: gitlabx_deploy_key.foo")
+					}
+					return fmt.Sprintf("%s:%s", rs.Primary.Attributes["project"], rs.Primary.ID), nil
+				},
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "gitlabx_deploy_key_enable.bar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Enable the key again after it has been disabled outside of
+			// Terraform
+			{
+				PreConfig: func() {
+					f.remove(bar())
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabDeployKeyConfig(1, testSSHKey, true),
+				Check: func(s *terraform.State) error {
+					if f.get(bar()) == nil {
+						return fmt.Errorf("the deploy key has not been enabled on bar-1")
+					}
+					return nil
+				},
+			},
+			// Add the key again so that it cannot push
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabDeployKeyConfig(1, testSSHKey, false),
+				Check:  resource.TestCheckResourceAttr("gitlabx_deploy_key.foo", "can_push", "false"),
+			},
+			{
+				Config:      testFakeGitlabConfig(f) + testAccGitlabDeployKeyConfig(1, "ssh-rsa AAAAC3NzaC1lZDI1NTE5AAAAIKxfVO9AJYHySh6ZkeY7hXYlGvaDf8g8DPot3raS47UZ", false),
+				ExpectError: regexp.MustCompile("key is an invalid SSH public key: the key is not of type ssh-rsa"),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabDeployKeyExists(n string, key *deployKey) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		id := rs.Primary.ID
+		if v, ok := rs.Primary.Attributes["key_id"]; ok {
+			id = v
+		}
+		keyId, err := strconv.Atoi(id)
+		if err != nil {
+			return err
+		}
+
+		conn := testAccProvider.Meta().(*Client)
+
+		got, _, err := getDeployKey(conn, rs.Primary.Attributes["project"], keyId)
+		if err != nil {
+			return err
+		}
+		*key = *got
+		return nil
+	}
+}
+
+func testAccCheckGitlabDeployKeyAttributes(key *deployKey, title string, canPush bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if key.Title != title {
+			return fmt.Errorf("got title %q; want %q", key.Title, title)
+		}
+		if key.CanPush != canPush {
+			return fmt.Errorf("got can_push %t; want %t", key.CanPush, canPush)
+		}
+		return nil
+	}
+}
+
+func testAccCheckGitlabDeployKeyDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gitlabx_deploy_key" {
+			continue
+		}
+
+		keyId, err := strconv.Atoi(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		_, resp, err := getDeployKey(conn, rs.Primary.Attributes["project"], keyId)
+		if err == nil {
+			return fmt.Errorf("Deploy key %s still exists", rs.Primary.ID)
+		}
+		if resp == nil || resp.StatusCode != 404 {
+			return err
+		}
+	}
+	return nil
+}
+
+func testAccGitlabDeployKeyConfig(rInt int, key string, canPush bool) string {
+	return fmt.Sprintf(`
+resource "gitlabx_project" "foo" {
+  name = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_project" "bar" {
+  name = "bar-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_deploy_key" "foo" {
+  project = "${gitlabx_project.foo.id}"
+  title = "deploy-%d"
+  key = "%s"
+  can_push = %t
+}
+
+resource "gitlabx_deploy_key_enable" "bar" {
+  project = "${gitlabx_project.bar.id}"
+  key_id = "${gitlabx_deploy_key.foo.id}"
+}
+	`, rInt, rInt, rInt, key, canPush)
+}
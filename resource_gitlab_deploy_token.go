@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// A deploy token belongs to either a project or a group; it cannot be changed,
+// and gitlab only returns the token itself when it is created.
+func resourceGitlabDeployToken() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGitlabDeployTokenCreate,
+		Read:   resourceGitlabDeployTokenRead,
+		Delete: resourceGitlabDeployTokenDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabDeployTokenImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:          schema.TypeString,
+				Description:   "The ID or path with namespace of the project; either project or group_id must be set.",
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"group_id"},
+			},
+			"group_id": {
+				Type:          schema.TypeString,
+				Description:   "The ID or full path of the group; either project or group_id must be set.",
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project"},
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Description: "The username to authenticate with the token; by default, gitlab generates one (e.g. gitlab+deploy-token-1).",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			"scopes": {
+				Type:        schema.TypeSet,
+				Description: "What the token grants access to: any of read_repository, read_registry, write_registry, read_package_registry and write_package_registry.",
+				Required:    true,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateValueFunc(deployTokenScopes),
+				},
+				Set: schema.HashString,
+			},
+			"expires_at": {
+				Type:         schema.TypeString,
+				Description:  "The date (in the YYYY-MM-DD format) when the token expires; by default it never does.",
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateDate,
+			},
+			"token": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+// deployTokenOwner returns the kind ("projects" or "groups") and the ID of the
+// owner of the deploy token.
+func deployTokenOwner(d *schema.ResourceData) (string, string, error) {
+	if v, ok := d.GetOk("project"); ok {
+		return "projects", v.(string), nil
+	}
+	if v, ok := d.GetOk("group_id"); ok {
+		return "groups", v.(string), nil
+	}
+	return "", "", fmt.Errorf("Either project or group_id must be set")
+}
+
+// deploy tokens are imported by a composite "project:<project>:<token id>" or
+// "group:<group>:<token id>" ID, where the project or group can be either its
+// numeric ID or its full path; the token itself cannot be imported.
+func resourceGitlabDeployTokenImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 3)
+	if len(parts) != 3 || (parts[0] != "project" && parts[0] != "group") || parts[1] == "" {
+		return nil, fmt.Errorf("Unexpected ID format (%q), expected project:<project>:<token id> or group:<group>:<token id>", d.Id())
+	}
+	if _, err := strconv.Atoi(parts[2]); err != nil {
+		return nil, fmt.Errorf("Invalid deploy token ID %q in import ID %q", parts[2], d.Id())
+	}
+
+	log.Printf("[DEBUG] import gitlab deploy token %s", d.Id())
+
+	if parts[0] == "project" {
+		d.Set("project", parts[1])
+	} else {
+		d.Set("group_id", parts[1])
+	}
+	d.SetId(parts[2])
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGitlabDeployTokenCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	kind, id, err := deployTokenOwner(d)
+	if err != nil {
+		return err
+	}
+	name := d.Get("name").(string)
+
+	options := &createDeployTokenOptions{
+		Name:   gitlab.String(name),
+		Scopes: []string{},
+	}
+
+	for _, scope := range d.Get("scopes").(*schema.Set).List() {
+		options.Scopes = append(options.Scopes, scope.(string))
+	}
+
+	if v, ok := d.GetOk("username"); ok {
+		options.Username = gitlab.String(v.(string))
+	}
+
+	if v, ok := d.GetOk("expires_at"); ok {
+		options.ExpiresAt = gitlab.String(v.(string))
+	}
+
+	token, err := createDeployToken(client, kind, id, options)
+	if err != nil {
+		return fmt.Errorf("Error creating deploy token %s for %s %s: %s", name, strings.TrimSuffix(kind, "s"), id, err)
+	}
+
+	d.SetId(fmt.Sprintf("%d", token.ID))
+	d.Set("token", token.Token)
+
+	return resourceGitlabDeployTokenRead(d, meta)
+}
+
+func resourceGitlabDeployTokenRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	kind, id, err := deployTokenOwner(d)
+	if err != nil {
+		return err
+	}
+	tokenId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] read gitlab deploy token %d of %s %s", tokenId, strings.TrimSuffix(kind, "s"), id)
+
+	token, response, err := getDeployToken(client, kind, id, tokenId)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing deploy token %d from state because its %s no longer exists in gitlab", tokenId, strings.TrimSuffix(kind, "s"))
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if token == nil || token.Revoked {
+		log.Printf("[WARN] removing deploy token %d from state because it no longer exists in gitlab", tokenId)
+		d.SetId("")
+		return nil
+	}
+
+	// gitlab returns the expiration date as a time, at midnight UTC
+	expiresAt := token.ExpiresAt
+	if len(expiresAt) > len("2006-01-02") {
+		expiresAt = expiresAt[:len("2006-01-02")]
+	}
+
+	d.Set("name", token.Name)
+	d.Set("username", token.Username)
+	d.Set("scopes", token.Scopes)
+	d.Set("expires_at", expiresAt)
+	return nil
+}
+
+func resourceGitlabDeployTokenDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	kind, id, err := deployTokenOwner(d)
+	if err != nil {
+		return err
+	}
+	tokenId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	return deleteDeployToken(client, kind, id, tokenId)
+}
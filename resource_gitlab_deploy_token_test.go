@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGitlabDeployToken_basic(t *testing.T) {
+	var token deployToken
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabDeployTokenDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabDeployTokenConfig(rInt, "read_repository"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabDeployTokenExists("gitlabx_deploy_token.project", &token),
+					resource.TestCheckResourceAttrSet("gitlabx_deploy_token.project", "token"),
+					resource.TestCheckResourceAttr("gitlabx_deploy_token.project", "expires_at", "2099-12-31"),
+					testAccCheckGitlabDeployTokenExists("gitlabx_deploy_token.group", &token),
+					resource.TestCheckResourceAttr("gitlabx_deploy_token.group", "username", fmt.Sprintf("deployer-%d", rInt)),
+				),
+			},
+			{
+				ResourceName: "gitlabx_deploy_token.group",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["gitlabx_deploy_token.group"]
+					if !ok {
+						return "", fmt.Errorf("Not Found: gitlabx_deploy_token.group")
+					}
+					return fmt.Sprintf("group:%s:%s", rs.Primary.Attributes["group_id"], rs.Primary.ID), nil
+				},
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"token"},
+			},
+		},
+	})
+}
+
+func TestGitlabDeployToken_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testCheckFakeGitlabCount(f, "projects", 0),
+			testCheckFakeGitlabCount(f, "groups", 0),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabDeployTokenConfig(1, "read_repository"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("gitlabx_deploy_token.project", "token", regexp.MustCompile("^fake-token-")),
+					resource.TestMatchResourceAttr("gitlabx_deploy_token.project", "username", regexp.MustCompile("^gitlab\\+deploy-token-")),
+					resource.TestCheckResourceAttr("gitlabx_deploy_token.project", "expires_at", "2099-12-31"),
+					resource.TestCheckResourceAttr("gitlabx_deploy_token.group", "username", "deployer-1"),
+					resource.TestCheckResourceAttr("gitlabx_deploy_token.group", "scopes.#", "2"),
+					testCheckFakeGitlabObject(f, "gitlabx_deploy_token.project", fakeObject{
+						"expires_at": "2099-12-31T00:00:00.000Z",
+					}, "projects/%s/deploy_tokens/%s", "project", "id"),
+				),
+			},
+			{
+				ResourceName: "gitlabx_deploy_token.project",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["gitlabx_deploy_token.project"]
+					if !ok {
+						return "", fmt.Errorf("Not Found: gitlabx_deploy_token.project")
+					}
+					return fmt.Sprintf("project:%s:%s", rs.Primary.Attributes["project"], rs.Primary.ID), nil
+				},
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"token"},
+			},
+			// Create the token again with different scopes
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabDeployTokenConfig(1, "read_registry"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlabx_deploy_token.project", "scopes.#", "1"),
+					func(s *terraform.State) error {
+						return testCheckFakeGitlabCount(f, f.find("projects", "path", "foo-1")+"/deploy_tokens", 1)(s)
+					},
+				),
+			},
+			// Create the token again after it has been revoked outside of
+			// Terraform
+			{
+				PreConfig: func() {
+					f.set(f.find(f.find("projects", "path", "foo-1")+"/deploy_tokens", "name", "deploy-1"), fakeObject{"revoked": true})
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabDeployTokenConfig(1, "read_registry"),
+				Check: func(s *terraform.State) error {
+					return testCheckFakeGitlabCount(f, f.find("projects", "path", "foo-1")+"/deploy_tokens", 2)(s)
+				},
+			},
+			// Create the token again after it has been deleted outside of
+			// Terraform
+			{
+				PreConfig: func() {
+					f.remove(f.find(f.find("groups", "path", "foo-1")+"/deploy_tokens", "name", "deploy-1"))
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabDeployTokenConfig(1, "read_registry"),
+				Check: func(s *terraform.State) error {
+					return testCheckFakeGitlabCount(f, f.find("groups", "path", "foo-1")+"/deploy_tokens", 1)(s)
+				},
+			},
+			// Forget the token after its project has been deleted outside of
+			// Terraform, creating them again
+			{
+				PreConfig: func() {
+					f.remove(f.find("projects", "path", "foo-1"))
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabDeployTokenConfig(1, "read_registry"),
+				Check: func(s *terraform.State) error {
+					return testCheckFakeGitlabCount(f, f.find("projects", "path", "foo-1")+"/deploy_tokens", 1)(s)
+				},
+			},
+			{
+				Config:      testFakeGitlabConfig(f) + testAccGitlabDeployTokenConfig(1, "write_repository"),
+				ExpectError: regexp.MustCompile("write_repository is an invalid value for argument scopes"),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabDeployTokenExists(n string, token *deployToken) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		kind, id := "projects", rs.Primary.Attributes["project"]
+		if id == "" {
+			kind, id = "groups", rs.Primary.Attributes["group_id"]
+		}
+		tokenId, err := strconv.Atoi(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		conn := testAccProvider.Meta().(*Client)
+
+		got, _, err := getDeployToken(conn, kind, id, tokenId)
+		if err != nil {
+			return err
+		}
+		if got == nil {
+			return fmt.Errorf("Deploy token %s does not exist", rs.Primary.ID)
+		}
+		*token = *got
+		return nil
+	}
+}
+
+func testAccCheckGitlabDeployTokenDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gitlabx_deploy_token" {
+			continue
+		}
+
+		kind, id := "projects", rs.Primary.Attributes["project"]
+		if id == "" {
+			kind, id = "groups", rs.Primary.Attributes["group_id"]
+		}
+		tokenId, err := strconv.Atoi(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		// the tokens of deleted projects and groups are gone with them
+		got, _, err := getDeployToken(conn, kind, id, tokenId)
+		if err == nil && got != nil && !got.Revoked {
+			return fmt.Errorf("Deploy token %s still exists", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+func testAccGitlabDeployTokenConfig(rInt int, scope string) string {
+	return fmt.Sprintf(`
+resource "gitlabx_project" "foo" {
+  name = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_group" "foo" {
+  name = "foo-%d"
+  path = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_deploy_token" "project" {
+  project = "${gitlabx_project.foo.id}"
+  name = "deploy-%d"
+  scopes = ["%s"]
+  expires_at = "2099-12-31"
+}
+
+resource "gitlabx_deploy_token" "group" {
+  group_id = "${gitlabx_group.foo.id}"
+  name = "deploy-%d"
+  username = "deployer-%d"
+  scopes = ["read_repository", "read_registry"]
+}
+	`, rInt, rInt, rInt, rInt, scope, rInt, rInt)
+}
@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/dihedron/terraform/helper/validation"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
@@ -16,6 +16,9 @@ func resourceGitlabGroup() *schema.Resource {
 		Read:   resourceGitlabGroupRead,
 		Update: resourceGitlabGroupUpdate,
 		Delete: resourceGitlabGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabGroupImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -46,6 +49,30 @@ func resourceGitlabGroup() *schema.Resource {
 				Optional:     true,
 				ValidateFunc: validation.StringInSlice([]string{"private", "internal", "public"}, true),
 			},
+			// the following field can be used to create the group as a
+			// subgroup of an existing group; changing it moves the group (and
+			// all its subgroups and projects) under the new parent through a
+			// group transfer; leaving it empty makes it a top-level group
+			"parent_id": {
+				Type:        schema.TypeInt,
+				Description: "The ID of the parent group, for nested subgroups; by default the group is a top-level one.",
+				Optional:    true,
+			},
+			// the user (ID or username) on behalf of whom the group is
+			// managed, overriding the provider-level sudo
+			"sudo": {
+				Type:        schema.TypeString,
+				Description: "The user (ID or username) to act as when managing the group; requires an admin token.",
+				Optional:    true,
+			},
+			"full_path": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"full_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
@@ -57,19 +84,40 @@ func resourceGitlabGroupSetToState(d *schema.ResourceData, group *gitlab.Group)
 	d.Set("lfs_enabled", group.LFSEnabled)
 	d.Set("request_access_enabled", group.RequestAccessEnabled)
 	d.Set("visibility_level", visibilityLevelToString(group.VisibilityLevel))
+	d.Set("parent_id", group.ParentID)
+	d.Set("full_path", group.FullPath)
+	d.Set("full_name", group.FullName)
+}
+
+// groups can be imported either by their numeric ID or by their full path
+// (e.g. "team/subteam"); either way the ID in the state is the numeric one.
+func resourceGitlabGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*Client)
+	log.Printf("[DEBUG] import gitlab group %s", d.Id())
+
+	group, _, err := getGroup(client, d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("Error importing group %s: %s", d.Id(), err)
+	}
+
+	d.SetId(fmt.Sprintf("%d", group.ID))
+	return []*schema.ResourceData{d}, nil
 }
 
 func resourceGitlabGroupExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(*gitlab.Client)
-	group, _, err := client.Groups.GetGroup(d.Id)
+	client := meta.(*Client)
+	group, response, err := getGroup(client, d.Id(), sudoOptions(d)...)
 	if group != nil && err == nil {
 		return true, nil
 	}
+	if response != nil && response.StatusCode == 404 {
+		return false, nil
+	}
 	return false, err
 }
 
 func resourceGitlabGroupCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gitlab.Client)
+	client := meta.(*Client)
 	options := &gitlab.CreateGroupOptions{
 		Name: gitlab.String(d.Get("name").(string)),
 		Path: gitlab.String(d.Get("path").(string)),
@@ -91,9 +139,13 @@ func resourceGitlabGroupCreate(d *schema.ResourceData, meta interface{}) error {
 		options.VisibilityLevel = stringToVisibilityLevel(v.(string))
 	}
 
+	if v, ok := d.GetOk("parent_id"); ok {
+		options.ParentID = gitlab.Int(v.(int))
+	}
+
 	log.Printf("[DEBUG] create gitlab group %q (path: %q)", options.Name, options.Path)
 
-	group, _, err := client.Groups.CreateGroup(options)
+	group, err := createGroup(client, options, sudoOptions(d)...)
 	if err != nil {
 		return err
 	}
@@ -104,10 +156,10 @@ func resourceGitlabGroupCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceGitlabGroupRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gitlab.Client)
+	client := meta.(*Client)
 	log.Printf("[DEBUG] read gitlab group %s", d.Id())
 
-	group, response, err := client.Groups.GetGroup(d.Id())
+	group, response, err := getGroup(client, d.Id(), sudoOptions(d)...)
 	if err != nil {
 		if response.StatusCode == 404 {
 			log.Printf("[WARN] removing group %s from state because it no longer exists in gitlab", d.Id())
@@ -122,7 +174,7 @@ func resourceGitlabGroupRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceGitlabGroupUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gitlab.Client)
+	client := meta.(*Client)
 
 	options := &gitlab.UpdateGroupOptions{}
 
@@ -152,18 +204,27 @@ func resourceGitlabGroupUpdate(d *schema.ResourceData, meta interface{}) error {
 
 	log.Printf("[DEBUG] update gitlab group %s", d.Id())
 
-	_, _, err := client.Groups.UpdateGroup(d.Id(), options)
+	err := updateGroup(client, d.Id(), options, sudoOptions(d)...)
 	if err != nil {
 		return err
 	}
 
+	// moving the group under a different parent is not a plain update: the
+	// whole subtree must be transferred, so that projects and subgroups are
+	// carried along instead of being destroyed and re-created
+	if d.HasChange("parent_id") {
+		if err := transferGroup(client, d.Id(), d.Get("parent_id").(int), sudoOptions(d)...); err != nil {
+			return err
+		}
+	}
+
 	return resourceGitlabGroupRead(d, meta)
 }
 
 func resourceGitlabGroupDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gitlab.Client)
+	client := meta.(*Client)
 	log.Printf("[DEBUG] Delete gitlab group %s", d.Id())
 
-	_, err := client.Groups.DeleteGroup(d.Id())
+	_, err := client.Groups.DeleteGroup(d.Id(), sudoOptions(d)...)
 	return err
 }
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceGitlabGroupMembers manages the whole list of members of a group:
+// unlike gitlabx_group_membership, it removes any member that has not been
+// declared, e.g. because it has been added through the UI.
+func resourceGitlabGroupMembers() *schema.Resource {
+	s := membersSchema([]string{"guest", "reporter", "developer", "master", "owner"})
+	s["group_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The ID or full path of the group.",
+		Required:    true,
+		ForceNew:    true,
+	}
+
+	return &schema.Resource{
+		Create: resourceGitlabGroupMembersCreate,
+		Read:   resourceGitlabGroupMembersRead,
+		Update: resourceGitlabGroupMembersUpdate,
+		Delete: resourceGitlabGroupMembersDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabGroupMembersImport,
+		},
+
+		Schema: s,
+	}
+}
+
+// the members of a group are imported by the group ID or full path.
+func resourceGitlabGroupMembersImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Printf("[DEBUG] import gitlab group members %s", d.Id())
+
+	d.Set("group_id", d.Id())
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGitlabGroupMembersCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	group := d.Get("group_id").(string)
+
+	if err := applyMembers(client, "groups", group, d); err != nil {
+		return err
+	}
+
+	d.SetId(group)
+
+	return resourceGitlabGroupMembersRead(d, meta)
+}
+
+func resourceGitlabGroupMembersRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	group := d.Get("group_id").(string)
+	log.Printf("[DEBUG] read gitlab group members %s", group)
+
+	members, response, err := listMembers(client, "groups", group)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing group members %s from state because the group no longer exists in gitlab", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	return setMembersToState(d, members)
+}
+
+func resourceGitlabGroupMembersUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	group := d.Get("group_id").(string)
+
+	if err := applyMembers(client, "groups", group, d); err != nil {
+		return err
+	}
+
+	return resourceGitlabGroupMembersRead(d, meta)
+}
+
+func resourceGitlabGroupMembersDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	group := d.Get("group_id").(string)
+
+	return removeMembers(client, "groups", group, d)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestGitlabGroupMembers_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+	developer := f.addUser("developer")
+	reporter := f.addUser("reporter")
+	intruder := f.addUser("intruder")
+	bot := f.addUser("group_99_bot")
+
+	members := func() string {
+		return f.find("groups", "path", "foo") + "/members"
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "groups", 0),
+		Steps: []resource.TestStep{
+			// The creator of the group, as its owner, is left alone
+			{
+				Config: testFakeGitlabConfig(f) + testFakeGitlabGroupMembersConfig(fmt.Sprintf(`
+  member {
+    user_id = %d
+    access_level = "developer"
+  }
+				`, developer)),
+				Check: func(s *terraform.State) error {
+					if err := testCheckFakeGitlabCount(f, members(), 2)(s); err != nil {
+						return err
+					}
+					if f.get(fmt.Sprintf("%s/%d", members(), 1)) == nil {
+						return fmt.Errorf("the owner of the group has been removed")
+					}
+					return nil
+				},
+			},
+			// Remove the members added outside of Terraform, except for bots
+			{
+				PreConfig: func() {
+					f.put(fmt.Sprintf("%s/%d", members(), intruder), fakeObject{"id": intruder, "username": "intruder", "access_level": 40})
+					f.put(fmt.Sprintf("%s/%d", members(), bot), fakeObject{"id": bot, "username": "group_99_bot", "access_level": 40})
+				},
+				Config: testFakeGitlabConfig(f) + testFakeGitlabGroupMembersConfig(fmt.Sprintf(`
+  member {
+    user_id = %d
+    access_level = "developer"
+  }
+				`, developer)),
+				Check: func(s *terraform.State) error {
+					if err := testCheckFakeGitlabCount(f, members(), 3)(s); err != nil {
+						return err
+					}
+					if f.get(fmt.Sprintf("%s/%d", members(), intruder)) != nil {
+						return fmt.Errorf("the undeclared member has not been removed")
+					}
+					if f.get(fmt.Sprintf("%s/%d", members(), bot)) == nil {
+						return fmt.Errorf("the bot user has been removed")
+					}
+					return nil
+				},
+			},
+			// Change a role, revert a change made outside of Terraform and
+			// add a member
+			{
+				PreConfig: func() {
+					f.set(fmt.Sprintf("%s/%d", members(), developer), fakeObject{"expires_at": "2099-12-31"})
+				},
+				Config: testFakeGitlabConfig(f) + testFakeGitlabGroupMembersConfig(fmt.Sprintf(`
+  member {
+    user_id = %d
+    access_level = "master"
+  }
+  member {
+    user_id = %d
+    access_level = "reporter"
+    expires_at = "2099-12-31"
+  }
+				`, developer, reporter)),
+				Check: func(s *terraform.State) error {
+					if err := testCheckFakeGitlabCount(f, members(), 4)(s); err != nil {
+						return err
+					}
+					if m := f.get(fmt.Sprintf("%s/%d", members(), developer)); m["access_level"] != 40 || m["expires_at"] != nil {
+						return fmt.Errorf("got member %v; want a master with no expiration date", m)
+					}
+					if m := f.get(fmt.Sprintf("%s/%d", members(), reporter)); m["access_level"] != 20 || m["expires_at"] != "2099-12-31" {
+						return fmt.Errorf("got member %v; want a reporter until 2099-12-31", m)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testFakeGitlabGroupMembersConfig(members string) string {
+	return fmt.Sprintf(`
+resource "gitlabx_group" "foo" {
+  name = "foo"
+  path = "foo"
+  visibility_level = "private"
+}
+
+resource "gitlabx_group_members" "foo" {
+  group_id = "${gitlabx_group.foo.id}"
+  ignore_owners = true
+  ignore_bots = true
+  %s
+}
+	`, members)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func resourceGitlabGroupMembership() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGitlabGroupMembershipCreate,
+		Read:   resourceGitlabGroupMembershipRead,
+		Update: resourceGitlabGroupMembershipUpdate,
+		Delete: resourceGitlabGroupMembershipDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabGroupMembershipImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"group_id": {
+				Type:        schema.TypeString,
+				Description: "The ID or full path of the group.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"user_id": {
+				Type:        schema.TypeInt,
+				Description: "The ID of the user to add to the group.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"access_level": {
+				Type:         schema.TypeString,
+				Description:  "The role of the user in the group: one of guest, reporter, developer, master or owner.",
+				Required:     true,
+				ValidateFunc: validateValueFunc([]string{"guest", "reporter", "developer", "master", "owner"}),
+			},
+			"expires_at": {
+				Type:         schema.TypeString,
+				Description:  "The date (in the YYYY-MM-DD format) when the membership expires; by default it never does.",
+				Optional:     true,
+				ValidateFunc: validateDate,
+			},
+		},
+	}
+}
+
+// group memberships are imported by a composite "<group>:<user id>" ID, where
+// the group can be either its numeric ID or its full path.
+func resourceGitlabGroupMembershipImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	group, user, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	userID, err := strconv.Atoi(user)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid user ID %q in import ID %q", user, d.Id())
+	}
+
+	log.Printf("[DEBUG] import gitlab group membership %s/%d", group, userID)
+
+	d.Set("group_id", group)
+	d.Set("user_id", userID)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGitlabGroupMembershipCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	group := d.Get("group_id").(string)
+	options := &memberOptions{
+		UserID:      gitlab.Int(d.Get("user_id").(int)),
+		AccessLevel: gitlab.Int(accessLevel(d.Get("access_level").(string))),
+	}
+
+	if v, ok := d.GetOk("expires_at"); ok {
+		options.ExpiresAt = gitlab.String(v.(string))
+	}
+
+	if _, err := addMember(client, "groups", group, options); err != nil {
+		return fmt.Errorf("Error adding user %d to group %s: %s", *options.UserID, group, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%d", group, *options.UserID))
+
+	return resourceGitlabGroupMembershipRead(d, meta)
+}
+
+func resourceGitlabGroupMembershipRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	group := d.Get("group_id").(string)
+	user := d.Get("user_id").(int)
+	log.Printf("[DEBUG] read gitlab group membership %s", d.Id())
+
+	member, response, err := getMember(client, "groups", group, user)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing group membership %s from state because it no longer exists in gitlab", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("access_level", accessLevelNames[member.AccessLevel])
+	d.Set("expires_at", member.ExpiresAt)
+	return nil
+}
+
+func resourceGitlabGroupMembershipUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	group := d.Get("group_id").(string)
+	user := d.Get("user_id").(int)
+
+	// the access level must always be given, even if unchanged
+	options := &memberOptions{
+		AccessLevel: gitlab.Int(accessLevel(d.Get("access_level").(string))),
+	}
+
+	if d.HasChange("expires_at") {
+		options.ExpiresAt = gitlab.String(d.Get("expires_at").(string))
+	}
+
+	if err := editMember(client, "groups", group, user, options); err != nil {
+		return fmt.Errorf("Error updating user %d in group %s: %s", user, group, err)
+	}
+
+	return resourceGitlabGroupMembershipRead(d, meta)
+}
+
+func resourceGitlabGroupMembershipDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	group := d.Get("group_id").(string)
+	user := d.Get("user_id").(int)
+
+	return removeMember(client, "groups", group, user)
+}
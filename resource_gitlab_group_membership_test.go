@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGitlabGroupMembership_basic(t *testing.T) {
+	var m member
+	rInt := acctest.RandInt()
+	user := testAccUserID(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabGroupMembershipDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabGroupMembershipConfig(rInt, user, "developer", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabGroupMembershipExists("gitlabx_group_membership.foo", &m),
+					testAccCheckGitlabMemberAttributes(&m, 30, ""),
+				),
+			},
+			// Change the role and set an expiration date
+			{
+				Config: testAccGitlabGroupMembershipConfig(rInt, user, "master", "2099-12-31"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabGroupMembershipExists("gitlabx_group_membership.foo", &m),
+					testAccCheckGitlabMemberAttributes(&m, 40, "2099-12-31"),
+				),
+			},
+			{
+				ResourceName:      "gitlabx_group_membership.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGitlabGroupMembership_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+	user := f.addUser("developer")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "groups", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabGroupMembershipConfig(1, user, "developer", ""),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_group_membership.foo", fakeObject{
+					"access_level": 30,
+					"expires_at":   nil,
+				}, "groups/%s/members/%s", "group_id", "user_id"),
+			},
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabGroupMembershipConfig(1, user, "master", "2099-12-31"),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_group_membership.foo", fakeObject{
+					"access_level": 40,
+					"expires_at":   "2099-12-31",
+				}, "groups/%s/members/%s", "group_id", "user_id"),
+			},
+			{
+				ResourceName:      "gitlabx_group_membership.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Revert a role changed outside of Terraform
+			{
+				PreConfig: func() {
+					f.set(fmt.Sprintf("%s/members/%d", f.find("groups", "path", "foo-1"), user), fakeObject{"access_level": 10})
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabGroupMembershipConfig(1, user, "master", "2099-12-31"),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_group_membership.foo", fakeObject{
+					"access_level": 40,
+				}, "groups/%s/members/%s", "group_id", "user_id"),
+			},
+			// Add the user again after it has been removed outside of Terraform
+			{
+				PreConfig: func() {
+					f.remove(fmt.Sprintf("%s/members/%d", f.find("groups", "path", "foo-1"), user))
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabGroupMembershipConfig(1, user, "master", ""),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_group_membership.foo", fakeObject{
+					"access_level": 40,
+					"expires_at":   nil,
+				}, "groups/%s/members/%s", "group_id", "user_id"),
+			},
+		},
+	})
+}
+
+func TestGitlabGroupMembership_invalidAccessLevel(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		Steps: []resource.TestStep{
+			{
+				Config:      testFakeGitlabConfig(f) + testAccGitlabGroupMembershipConfig(1, 1, "admin", ""),
+				ExpectError: regexp.MustCompile("admin is an invalid value for argument access_level"),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabGroupMembershipExists(n string, m *member) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		user, err := strconv.Atoi(rs.Primary.Attributes["user_id"])
+		if err != nil {
+			return err
+		}
+		conn := testAccProvider.Meta().(*Client)
+
+		got, _, err := getMember(conn, "groups", rs.Primary.Attributes["group_id"], user)
+		if err != nil {
+			return err
+		}
+		*m = *got
+		return nil
+	}
+}
+
+func testAccCheckGitlabMemberAttributes(m *member, accessLevel int, expiresAt string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if m.AccessLevel != accessLevel {
+			return fmt.Errorf("got access level %d; want %d", m.AccessLevel, accessLevel)
+		}
+		if m.ExpiresAt != expiresAt {
+			return fmt.Errorf("got expires_at %q; want %q", m.ExpiresAt, expiresAt)
+		}
+		return nil
+	}
+}
+
+func testAccCheckGitlabGroupMembershipDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gitlabx_group_membership" {
+			continue
+		}
+
+		user, err := strconv.Atoi(rs.Primary.Attributes["user_id"])
+		if err != nil {
+			return err
+		}
+		_, resp, err := getMember(conn, "groups", rs.Primary.Attributes["group_id"], user)
+		if err == nil {
+			return fmt.Errorf("Group membership %s still exists", rs.Primary.ID)
+		}
+		if resp == nil || resp.StatusCode != 404 {
+			return err
+		}
+	}
+	return nil
+}
+
+func testAccGitlabGroupMembershipConfig(rInt, user int, accessLevel, expiresAt string) string {
+	if expiresAt != "" {
+		expiresAt = fmt.Sprintf("expires_at = %q", expiresAt)
+	}
+	return fmt.Sprintf(`
+resource "gitlabx_group" "foo" {
+  name = "foo-%d"
+  path = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_group_membership" "foo" {
+  group_id = "${gitlabx_group.foo.id}"
+  user_id = %d
+  access_level = "%s"
+  %s
+}
+	`, rInt, rInt, user, accessLevel, expiresAt)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func resourceGitlabGroupShareGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGitlabGroupShareGroupCreate,
+		Read:   resourceGitlabGroupShareGroupRead,
+		Delete: resourceGitlabGroupShareGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabGroupShareGroupImport,
+		},
+
+		// shares cannot be updated, only removed and added again
+		Schema: map[string]*schema.Schema{
+			"group": {
+				Type:        schema.TypeString,
+				Description: "The ID or full path of the group being shared.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"group_id": {
+				Type:        schema.TypeInt,
+				Description: "The ID of the group to share the group with.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"group_access": {
+				Type:         schema.TypeString,
+				Description:  "The maximum role of the members of the other group in the group: one of guest, reporter, developer, master or owner.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateValueFunc([]string{"guest", "reporter", "developer", "master", "owner"}),
+			},
+			"expires_at": {
+				Type:         schema.TypeString,
+				Description:  "The date (in the YYYY-MM-DD format) when the share expires; by default it never does.",
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateDate,
+			},
+		},
+	}
+}
+
+// group shares are imported by a composite "<group>:<group id>" ID, where the
+// group being shared can be either its numeric ID or its full path, and the
+// group it is shared with is given by its numeric ID, as in group_id.
+func resourceGitlabGroupShareGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	group, shareGroup, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	shareGroupID, err := strconv.Atoi(shareGroup)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid group ID %q in import ID %q", shareGroup, d.Id())
+	}
+
+	log.Printf("[DEBUG] import gitlab group share %s/%d", group, shareGroupID)
+
+	d.Set("group", group)
+	d.Set("group_id", shareGroupID)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGitlabGroupShareGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	group := d.Get("group").(string)
+	options := &shareOptions{
+		GroupID:     gitlab.Int(d.Get("group_id").(int)),
+		GroupAccess: gitlab.Int(accessLevel(d.Get("group_access").(string))),
+	}
+
+	if v, ok := d.GetOk("expires_at"); ok {
+		options.ExpiresAt = gitlab.String(v.(string))
+	}
+
+	if err := shareWithGroup(client, "groups", group, options); err != nil {
+		return fmt.Errorf("Error sharing group %s with group %d: %s", group, *options.GroupID, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%d", group, *options.GroupID))
+
+	return resourceGitlabGroupShareGroupRead(d, meta)
+}
+
+func resourceGitlabGroupShareGroupRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	group := d.Get("group").(string)
+	shareGroup := d.Get("group_id").(int)
+	log.Printf("[DEBUG] read gitlab group share %s", d.Id())
+
+	shared, response, err := getSharedGroup(client, "groups", group, shareGroup)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing group share %s from state because the group no longer exists in gitlab", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if shared == nil {
+		log.Printf("[WARN] removing group share %s from state because it no longer exists in gitlab", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("group_access", accessLevelNames[shared.GroupAccessLevel])
+	d.Set("expires_at", shared.ExpiresAt)
+	return nil
+}
+
+func resourceGitlabGroupShareGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	group := d.Get("group").(string)
+	shareGroup := d.Get("group_id").(int)
+
+	return unshareWithGroup(client, "groups", group, shareGroup)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGitlabGroupShareGroup_basic(t *testing.T) {
+	var shared sharedGroup
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabGroupShareGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabGroupShareGroupConfig(rInt, "guest"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabGroupShareGroupExists("gitlabx_group_share_group.foo", &shared),
+					testAccCheckGitlabSharedGroupAttributes(&shared, 10, ""),
+				),
+			},
+			{
+				ResourceName:      "gitlabx_group_share_group.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGitlabGroupShareGroup_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "groups", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabGroupShareGroupConfig(1, "guest"),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_group_share_group.foo", fakeObject{
+					"group_access_level": 10,
+				}, "groups/%s/share/%s", "group", "group_id"),
+			},
+			{
+				ResourceName:      "gitlabx_group_share_group.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Share the group again with a different access level
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabGroupShareGroupConfig(1, "developer"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckFakeGitlabObject(f, "gitlabx_group_share_group.foo", fakeObject{
+						"group_access_level": 30,
+					}, "groups/%s/share/%s", "group", "group_id"),
+					func(s *terraform.State) error {
+						return testCheckFakeGitlabCount(f, f.find("groups", "path", "shared-1")+"/share", 1)(s)
+					},
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabGroupShareGroupExists(n string, shared *sharedGroup) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		group, err := strconv.Atoi(rs.Primary.Attributes["group_id"])
+		if err != nil {
+			return err
+		}
+		conn := testAccProvider.Meta().(*Client)
+
+		got, _, err := getSharedGroup(conn, "groups", rs.Primary.Attributes["group"], group)
+		if err != nil {
+			return err
+		}
+		if got == nil {
+			return fmt.Errorf("Group %s is not shared with group %d", rs.Primary.Attributes["group"], group)
+		}
+		*shared = *got
+		return nil
+	}
+}
+
+func testAccCheckGitlabGroupShareGroupDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gitlabx_group_share_group" {
+			continue
+		}
+
+		group, err := strconv.Atoi(rs.Primary.Attributes["group_id"])
+		if err != nil {
+			return err
+		}
+		shared, resp, err := getSharedGroup(conn, "groups", rs.Primary.Attributes["group"], group)
+		if err == nil && shared != nil {
+			return fmt.Errorf("Group share %s still exists", rs.Primary.ID)
+		}
+		if err != nil && (resp == nil || resp.StatusCode != 404) {
+			return err
+		}
+	}
+	return nil
+}
+
+func testAccGitlabGroupShareGroupConfig(rInt int, groupAccess string) string {
+	return fmt.Sprintf(`
+resource "gitlabx_group" "shared" {
+  name = "shared-%d"
+  path = "shared-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_group" "foo" {
+  name = "foo-%d"
+  path = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_group_share_group" "foo" {
+  group = "${gitlabx_group.shared.id}"
+  group_id = "${gitlabx_group.foo.id}"
+  group_access = "%s"
+}
+	`, rInt, rInt, rInt, rInt, groupAccess)
+}
@@ -72,6 +72,79 @@ func TestAccGitlabGroup_basic(t *testing.T) {
 	})
 }
 
+func TestAccGitlabGroup_import(t *testing.T) {
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabGroupConfig(rInt),
+			},
+			// Import by numeric ID
+			{
+				ResourceName:      "gitlabx_group.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Import by full path
+			{
+				ResourceName:      "gitlabx_group.foo",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("bar-%d", rInt),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccGitlabGroup_nested(t *testing.T) {
+	var group gitlab.Group
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabGroupDestroy,
+		Steps: []resource.TestStep{
+			// Create a subgroup under the first parent
+			{
+				Config: testAccGitlabGroupNestedConfig(rInt, "parent1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabGroupExists("gitlabx_group.foo", &group),
+					testAccCheckGitlabGroupParent(&group, "gitlabx_group.parent1"),
+					resource.TestCheckResourceAttr("gitlabx_group.foo", "full_path", fmt.Sprintf("parent1-%d/bar-%d", rInt, rInt)),
+				),
+			},
+			// Move the subgroup under the second parent
+			{
+				Config: testAccGitlabGroupNestedConfig(rInt, "parent2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabGroupExists("gitlabx_group.foo", &group),
+					testAccCheckGitlabGroupParent(&group, "gitlabx_group.parent2"),
+					resource.TestCheckResourceAttr("gitlabx_group.foo", "full_path", fmt.Sprintf("parent2-%d/bar-%d", rInt, rInt)),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabGroupParent(group *gitlab.Group, n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		if fmt.Sprintf("%d", group.ParentID) != rs.Primary.ID {
+			return fmt.Errorf("got parent_id %d; want %s", group.ParentID, rs.Primary.ID)
+		}
+		return nil
+	}
+}
+
 func testAccCheckGitlabGroupExists(n string, group *gitlab.Group) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -83,9 +156,9 @@ func testAccCheckGitlabGroupExists(n string, group *gitlab.Group) resource.TestC
 		if repoName == "" {
 			return fmt.Errorf("No group ID is set")
 		}
-		conn := testAccProvider.Meta().(*gitlab.Client)
+		conn := testAccProvider.Meta().(*Client)
 
-		gotGroup, _, err := conn.Groups.GetGroup(repoName)
+		gotGroup, _, err := getGroup(conn, repoName)
 		if err != nil {
 			return err
 		}
@@ -134,14 +207,14 @@ func testAccCheckGitlabGroupAttributes(group *gitlab.Group, want *testAccGitlabG
 }
 
 func testAccCheckGitlabGroupDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*gitlab.Client)
+	conn := testAccProvider.Meta().(*Client)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "gitlabx_group" {
 			continue
 		}
 
-		gotRepo, resp, err := conn.Groups.GetGroup(rs.Primary.ID)
+		gotRepo, resp, err := getGroup(conn, rs.Primary.ID)
 		if err == nil {
 			if gotRepo != nil && fmt.Sprintf("%d", gotRepo.ID) == rs.Primary.ID {
 				return fmt.Errorf("Repository still exists")
@@ -185,3 +258,118 @@ resource "gitlabx_group" "foo" {
 }
 	`, rInt, rInt)
 }
+
+func testAccGitlabGroupNestedConfig(rInt int, parent string) string {
+	return fmt.Sprintf(`
+resource "gitlabx_group" "parent1" {
+  name = "parent1-%d"
+  path = "parent1-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_group" "parent2" {
+  name = "parent2-%d"
+  path = "parent2-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_group" "foo" {
+  name = "foo-%d"
+  path = "bar-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+  parent_id = "${gitlabx_group.%s.id}"
+}
+	`, rInt, rInt, rInt, rInt, rInt, rInt, parent)
+}
+
+func TestGitlabGroup_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "groups", 0),
+		Steps: []resource.TestStep{
+			// Create a group
+			{
+				Config: testFakeGitlabConfig(f) + testFakeGitlabGroupConfig("Terraform unit tests"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlabx_group.foo", "full_path", "bar"),
+					testCheckFakeGitlabObject(f, "gitlabx_group.foo", fakeObject{
+						"name":        "foo",
+						"description": "Terraform unit tests",
+						"visibility":  "internal",
+					}, "groups/%s", "id"),
+				),
+			},
+			// Update the group
+			{
+				Config: testFakeGitlabConfig(f) + testFakeGitlabGroupConfig("Terraform unit tests!"),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_group.foo", fakeObject{
+					"description": "Terraform unit tests!",
+				}, "groups/%s", "id"),
+			},
+			// Revert a change made outside of Terraform
+			{
+				PreConfig: func() {
+					f.set(f.find("groups", "path", "bar"), fakeObject{"description": "changed"})
+				},
+				Config: testFakeGitlabConfig(f) + testFakeGitlabGroupConfig("Terraform unit tests!"),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_group.foo", fakeObject{
+					"description": "Terraform unit tests!",
+				}, "groups/%s", "id"),
+			},
+			// Re-create the group after it has been deleted outside of Terraform
+			{
+				PreConfig: func() {
+					f.remove(f.find("groups", "path", "bar"))
+				},
+				Config: testFakeGitlabConfig(f) + testFakeGitlabGroupConfig("Terraform unit tests!"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckFakeGitlabCount(f, "groups", 1),
+					testCheckFakeGitlabObject(f, "gitlabx_group.foo", fakeObject{
+						"description": "Terraform unit tests!",
+					}, "groups/%s", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestGitlabGroup_fakeNested(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "groups", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabGroupNestedConfig(1, "parent1"),
+				Check:  resource.TestCheckResourceAttr("gitlabx_group.foo", "full_path", "parent1-1/bar-1"),
+			},
+			// Transfer the group to the other parent
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabGroupNestedConfig(1, "parent2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlabx_group.foo", "full_path", "parent2-1/bar-1"),
+					resource.TestCheckResourceAttr("gitlabx_group.foo", "full_name", "parent2-1 / foo-1"),
+				),
+			},
+		},
+	})
+}
+
+func testFakeGitlabGroupConfig(description string) string {
+	return fmt.Sprintf(`
+resource "gitlabx_group" "foo" {
+  name = "foo"
+  path = "bar"
+  description = "%s"
+  visibility_level = "internal"
+}
+	`, description)
+}
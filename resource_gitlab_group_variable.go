@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceGitlabGroupVariable() *schema.Resource {
+	s := variableSchema()
+	s["group_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The ID or full path of the group.",
+		Required:    true,
+		ForceNew:    true,
+	}
+
+	return &schema.Resource{
+		Create: resourceGitlabGroupVariableCreate,
+		Read:   resourceGitlabGroupVariableRead,
+		Update: resourceGitlabGroupVariableUpdate,
+		Delete: resourceGitlabGroupVariableDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabGroupVariableImport,
+		},
+
+		Schema: s,
+	}
+}
+
+// group variables are imported by a composite "<group>:<key>:<environment scope>"
+// ID, where the group can be either its numeric ID or its full path.
+func resourceGitlabGroupVariableImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	group, key, scope, err := parseVariableID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[DEBUG] import gitlab group variable %s", d.Id())
+
+	d.Set("group_id", group)
+	d.Set("key", key)
+	d.Set("environment_scope", scope)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGitlabGroupVariableCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	group := d.Get("group_id").(string)
+	key := d.Get("key").(string)
+	scope := d.Get("environment_scope").(string)
+
+	if err := createVariable(client, "groups", group, expandVariable(variableSettings(d))); err != nil {
+		return fmt.Errorf("Error creating variable %s of group %s: %s", key, group, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s:%s", group, key, scope))
+
+	return resourceGitlabGroupVariableRead(d, meta)
+}
+
+func resourceGitlabGroupVariableRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	return readVariable(d, client, "groups", d.Get("group_id").(string))
+}
+
+func resourceGitlabGroupVariableUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	group := d.Get("group_id").(string)
+	key := d.Get("key").(string)
+	scope := d.Get("environment_scope").(string)
+
+	if err := updateVariable(client, "groups", group, key, scope, expandVariable(variableSettings(d))); err != nil {
+		return fmt.Errorf("Error updating variable %s of group %s: %s", key, group, err)
+	}
+
+	return resourceGitlabGroupVariableRead(d, meta)
+}
+
+func resourceGitlabGroupVariableDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	return deleteVariable(client, "groups", d.Get("group_id").(string), d.Get("key").(string), d.Get("environment_scope").(string))
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGitlabGroupVariable_basic(t *testing.T) {
+	var v variable
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabVariableDestroy("gitlabx_group_variable", "groups", "group_id"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabGroupVariableConfig(rInt, "env_var"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabVariableExists("gitlabx_group_variable.foo", "groups", "group_id", &v),
+					testAccCheckGitlabVariableAttributes(&v, "-----BEGIN CERTIFICATE-----\n", true),
+				),
+			},
+			// Turn the variable into a file
+			{
+				Config: testAccGitlabGroupVariableConfig(rInt, "file"),
+				Check:  resource.TestCheckResourceAttr("gitlabx_group_variable.foo", "variable_type", "file"),
+			},
+			{
+				ResourceName:      "gitlabx_group_variable.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGitlabGroupVariable_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	path := func() string {
+		return f.find("groups", "path", "foo-1") + "/variables/CA_CERT:" + url.PathEscape("*")
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "groups", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabGroupVariableConfig(1, "env_var"),
+				Check: func(s *terraform.State) error {
+					object := f.get(path())
+					if object == nil || object["protected"] != true || object["value"] != "-----BEGIN CERTIFICATE-----\n" {
+						return fmt.Errorf("got variable %v; want a protected certificate", object)
+					}
+					return nil
+				},
+			},
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabGroupVariableConfig(1, "file"),
+				Check: func(s *terraform.State) error {
+					if object := f.get(path()); object["variable_type"] != "file" {
+						return fmt.Errorf("got variable type %v; want file", object["variable_type"])
+					}
+					return nil
+				},
+			},
+			{
+				ResourceName:      "gitlabx_group_variable.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update the variable again after it has been changed outside of
+			// Terraform
+			{
+				PreConfig: func() {
+					f.set(path(), fakeObject{"variable_type": "env_var"})
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabGroupVariableConfig(1, "file"),
+				Check: func(s *terraform.State) error {
+					if object := f.get(path()); object["variable_type"] != "file" {
+						return fmt.Errorf("got variable type %v; want file", object["variable_type"])
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccGitlabGroupVariableConfig(rInt int, variableType string) string {
+	return fmt.Sprintf(`
+resource "gitlabx_group" "foo" {
+  name = "foo-%d"
+  path = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_group_variable" "foo" {
+  group_id = "${gitlabx_group.foo.id}"
+  key = "CA_CERT"
+  value = "-----BEGIN CERTIFICATE-----\n"
+  variable_type = "%s"
+  protected = true
+}
+	`, rInt, rInt, variableType)
+}
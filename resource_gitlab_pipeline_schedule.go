@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func resourceGitlabPipelineSchedule() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGitlabPipelineScheduleCreate,
+		Read:   resourceGitlabPipelineScheduleRead,
+		Update: resourceGitlabPipelineScheduleUpdate,
+		Delete: resourceGitlabPipelineScheduleDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabPipelineScheduleImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Description: "The ID or path with namespace of the project.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"ref": {
+				Type:        schema.TypeString,
+				Description: "The branch or tag to run the pipeline on.",
+				Required:    true,
+			},
+			"cron": {
+				Type:         schema.TypeString,
+				Description:  "When to run the pipeline, as a cron expression (e.g. \"0 1 * * *\").",
+				Required:     true,
+				ValidateFunc: validateCron,
+			},
+			"cron_timezone": {
+				Type:        schema.TypeString,
+				Description: "The timezone of the cron expression (e.g. Europe/Rome).",
+				Optional:    true,
+				Default:     "UTC",
+			},
+			"active": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"variable": {
+				Type:        schema.TypeSet,
+				Description: "The variables set in the pipelines run by the schedule.",
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateVariableKey,
+						},
+						"value": {
+							Type:      schema.TypeString,
+							Required:  true,
+							Sensitive: true,
+						},
+						"variable_type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "env_var",
+							ValidateFunc: validateValueFunc(variableTypes),
+						},
+					},
+				},
+			},
+			"next_run_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// pipeline schedules are imported by a composite "<project>:<schedule id>"
+// ID, where the project can be either its numeric ID or its path with
+// namespace.
+func resourceGitlabPipelineScheduleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	project, scheduleId, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := strconv.Atoi(scheduleId); err != nil {
+		return nil, fmt.Errorf("Invalid pipeline schedule ID %q in import ID %q", scheduleId, d.Id())
+	}
+
+	log.Printf("[DEBUG] import gitlab pipeline schedule %s/%s", project, scheduleId)
+
+	d.Set("project", project)
+	d.SetId(scheduleId)
+	return []*schema.ResourceData{d}, nil
+}
+
+// expandPipelineScheduleVariables returns the variables in the given set, by
+// key.
+func expandPipelineScheduleVariables(set *schema.Set) map[string]*pipelineScheduleVariable {
+	variables := map[string]*pipelineScheduleVariable{}
+	for _, v := range set.List() {
+		m := v.(map[string]interface{})
+		variables[m["key"].(string)] = &pipelineScheduleVariable{
+			Key:          m["key"].(string),
+			Value:        m["value"].(string),
+			VariableType: m["variable_type"].(string),
+		}
+	}
+	return variables
+}
+
+// applyPipelineScheduleVariables creates, updates and deletes the variables
+// of a pipeline schedule, so that the old ones become the new ones.
+func applyPipelineScheduleVariables(client *Client, project string, schedule int, old, new *schema.Set) error {
+	actual := expandPipelineScheduleVariables(old)
+	declared := expandPipelineScheduleVariables(new)
+
+	for key, want := range declared {
+		options := &pipelineScheduleVariableOptions{
+			Value:        gitlab.String(want.Value),
+			VariableType: gitlab.String(want.VariableType),
+		}
+		got, ok := actual[key]
+		switch {
+		case !ok:
+			options.Key = gitlab.String(key)
+			if err := createPipelineScheduleVariable(client, project, schedule, options); err != nil {
+				return fmt.Errorf("Error creating variable %s of pipeline schedule %d: %s", key, schedule, err)
+			}
+		case *got != *want:
+			if err := editPipelineScheduleVariable(client, project, schedule, key, options); err != nil {
+				return fmt.Errorf("Error updating variable %s of pipeline schedule %d: %s", key, schedule, err)
+			}
+		}
+	}
+
+	for key := range actual {
+		if _, ok := declared[key]; ok {
+			continue
+		}
+		if err := deletePipelineScheduleVariable(client, project, schedule, key); err != nil {
+			return fmt.Errorf("Error deleting variable %s of pipeline schedule %d: %s", key, schedule, err)
+		}
+	}
+	return nil
+}
+
+func resourceGitlabPipelineScheduleCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	options := &pipelineScheduleOptions{
+		Description:  gitlab.String(d.Get("description").(string)),
+		Ref:          gitlab.String(d.Get("ref").(string)),
+		Cron:         gitlab.String(d.Get("cron").(string)),
+		CronTimezone: gitlab.String(d.Get("cron_timezone").(string)),
+		Active:       gitlab.Bool(d.Get("active").(bool)),
+	}
+
+	schedule, err := createPipelineSchedule(client, project, options)
+	if err != nil {
+		return fmt.Errorf("Error creating pipeline schedule %q in project %s: %s", *options.Description, project, err)
+	}
+
+	d.SetId(fmt.Sprintf("%d", schedule.ID))
+
+	variables := d.Get("variable").(*schema.Set)
+	if err := applyPipelineScheduleVariables(client, project, schedule.ID, schema.NewSet(variables.F, nil), variables); err != nil {
+		return err
+	}
+
+	return resourceGitlabPipelineScheduleRead(d, meta)
+}
+
+func resourceGitlabPipelineScheduleRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	scheduleId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] read gitlab pipeline schedule %s/%d", project, scheduleId)
+
+	schedule, response, err := getPipelineSchedule(client, project, scheduleId)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing pipeline schedule %d from state because it no longer exists in gitlab", scheduleId)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	variables := []interface{}{}
+	for _, v := range schedule.Variables {
+		variables = append(variables, map[string]interface{}{
+			"key":           v.Key,
+			"value":         v.Value,
+			"variable_type": v.VariableType,
+		})
+	}
+
+	d.Set("description", schedule.Description)
+	d.Set("ref", schedule.Ref)
+	d.Set("cron", schedule.Cron)
+	d.Set("cron_timezone", schedule.CronTimezone)
+	d.Set("active", schedule.Active)
+	d.Set("next_run_at", schedule.NextRunAt)
+	return d.Set("variable", variables)
+}
+
+func resourceGitlabPipelineScheduleUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	scheduleId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	if d.HasChange("description") || d.HasChange("ref") || d.HasChange("cron") || d.HasChange("cron_timezone") || d.HasChange("active") {
+		options := &pipelineScheduleOptions{
+			Description:  gitlab.String(d.Get("description").(string)),
+			Ref:          gitlab.String(d.Get("ref").(string)),
+			Cron:         gitlab.String(d.Get("cron").(string)),
+			CronTimezone: gitlab.String(d.Get("cron_timezone").(string)),
+			Active:       gitlab.Bool(d.Get("active").(bool)),
+		}
+
+		if err := editPipelineSchedule(client, project, scheduleId, options); err != nil {
+			return fmt.Errorf("Error updating pipeline schedule %d of project %s: %s", scheduleId, project, err)
+		}
+	}
+
+	if d.HasChange("variable") {
+		old, new := d.GetChange("variable")
+		if err := applyPipelineScheduleVariables(client, project, scheduleId, old.(*schema.Set), new.(*schema.Set)); err != nil {
+			return err
+		}
+	}
+
+	return resourceGitlabPipelineScheduleRead(d, meta)
+}
+
+func resourceGitlabPipelineScheduleDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	scheduleId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	return deletePipelineSchedule(client, project, scheduleId)
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGitlabPipelineSchedule_basic(t *testing.T) {
+	var schedule pipelineSchedule
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabPipelineScheduleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabPipelineScheduleConfig(rInt, "0 1 * * *", true, testAccGitlabPipelineScheduleVariables),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabPipelineScheduleExists("gitlabx_pipeline_schedule.foo", &schedule),
+					testAccCheckGitlabPipelineScheduleAttributes(&schedule, "0 1 * * *", true, map[string]string{"DEPLOY": "false", "SUITE": "full"}),
+				),
+			},
+			// Update the schedule and its variables
+			{
+				Config: testAccGitlabPipelineScheduleConfig(rInt, "30 2 * * 1-5", false, testAccGitlabPipelineScheduleVariablesUpdated),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabPipelineScheduleExists("gitlabx_pipeline_schedule.foo", &schedule),
+					testAccCheckGitlabPipelineScheduleAttributes(&schedule, "30 2 * * 1-5", false, map[string]string{"SUITE": "smoke", "NOTIFY": "true"}),
+				),
+			},
+			{
+				ResourceName: "gitlabx_pipeline_schedule.foo",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["gitlabx_pipeline_schedule.foo"]
+					if !ok {
+						return "", fmt.Errorf("Not Found: gitlabx_pipeline_schedule.foo")
+					}
+					return fmt.Sprintf("%s:%s", rs.Primary.Attributes["project"], rs.Primary.ID), nil
+				},
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGitlabPipelineSchedule_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	var schedule pipelineSchedule
+	fetch := func(s *terraform.State) error {
+		path := f.find(f.find("projects", "path", "foo-1")+"/pipeline_schedules", "description", "Nightly build")
+		object := f.get(path)
+		if object == nil {
+			return fmt.Errorf("the pipeline schedule does not exist")
+		}
+		schedule = pipelineSchedule{Cron: object["cron"].(string), Active: object["active"].(bool)}
+		for _, v := range object["variables"].([]fakeObject) {
+			schedule.Variables = append(schedule.Variables, &pipelineScheduleVariable{Key: v["key"].(string), Value: v["value"].(string)})
+		}
+		return nil
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "projects", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabPipelineScheduleConfig(1, "0 1 * * *", true, testAccGitlabPipelineScheduleVariables),
+				Check: resource.ComposeTestCheckFunc(
+					fetch,
+					testAccCheckGitlabPipelineScheduleAttributes(&schedule, "0 1 * * *", true, map[string]string{"DEPLOY": "false", "SUITE": "full"}),
+					resource.TestCheckResourceAttr("gitlabx_pipeline_schedule.foo", "cron_timezone", "UTC"),
+					resource.TestCheckResourceAttrSet("gitlabx_pipeline_schedule.foo", "next_run_at"),
+				),
+			},
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabPipelineScheduleConfig(1, "30 2 * * 1-5", false, testAccGitlabPipelineScheduleVariablesUpdated),
+				Check: resource.ComposeTestCheckFunc(
+					fetch,
+					testAccCheckGitlabPipelineScheduleAttributes(&schedule, "30 2 * * 1-5", false, map[string]string{"SUITE": "smoke", "NOTIFY": "true"}),
+				),
+			},
+			{
+				ResourceName: "gitlabx_pipeline_schedule.foo",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["gitlabx_pipeline_schedule.foo"]
+					if !ok {
+						return "", fmt.Errorf("Not Found: gitlabx_pipeline_schedule.foo")
+					}
+					return fmt.Sprintf("%s:%s", rs.Primary.Attributes["project"], rs.Primary.ID), nil
+				},
+				ImportStateVerify: true,
+			},
+			// Update the schedule again after it has been changed outside of
+			// Terraform
+			{
+				PreConfig: func() {
+					path := f.find(f.find("projects", "path", "foo-1")+"/pipeline_schedules", "description", "Nightly build")
+					f.set(path, fakeObject{
+						"active":    true,
+						"variables": []fakeObject{{"key": "SUITE", "value": "full", "variable_type": "env_var"}},
+					})
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabPipelineScheduleConfig(1, "30 2 * * 1-5", false, testAccGitlabPipelineScheduleVariablesUpdated),
+				Check: resource.ComposeTestCheckFunc(
+					fetch,
+					testAccCheckGitlabPipelineScheduleAttributes(&schedule, "30 2 * * 1-5", false, map[string]string{"SUITE": "smoke", "NOTIFY": "true"}),
+				),
+			},
+			{
+				Config:      testFakeGitlabConfig(f) + testAccGitlabPipelineScheduleConfig(1, "0 25 * * *", false, ""),
+				ExpectError: regexp.MustCompile(`invalid hour "25": 25 is not between 0 and 23`),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabPipelineScheduleExists(n string, schedule *pipelineSchedule) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		scheduleId, err := strconv.Atoi(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		conn := testAccProvider.Meta().(*Client)
+
+		got, _, err := getPipelineSchedule(conn, rs.Primary.Attributes["project"], scheduleId)
+		if err != nil {
+			return err
+		}
+		*schedule = *got
+		return nil
+	}
+}
+
+func testAccCheckGitlabPipelineScheduleAttributes(schedule *pipelineSchedule, cron string, active bool, variables map[string]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if schedule.Cron != cron {
+			return fmt.Errorf("got cron %q; want %q", schedule.Cron, cron)
+		}
+		if schedule.Active != active {
+			return fmt.Errorf("got active %t; want %t", schedule.Active, active)
+		}
+		got := map[string]string{}
+		for _, v := range schedule.Variables {
+			got[v.Key] = v.Value
+		}
+		if !reflect.DeepEqual(got, variables) {
+			return fmt.Errorf("got variables %v; want %v", got, variables)
+		}
+		return nil
+	}
+}
+
+func testAccCheckGitlabPipelineScheduleDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gitlabx_pipeline_schedule" {
+			continue
+		}
+
+		scheduleId, err := strconv.Atoi(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		_, resp, err := getPipelineSchedule(conn, rs.Primary.Attributes["project"], scheduleId)
+		if err == nil {
+			return fmt.Errorf("Pipeline schedule %s still exists", rs.Primary.ID)
+		}
+		if resp == nil || resp.StatusCode != 404 {
+			return err
+		}
+	}
+	return nil
+}
+
+const testAccGitlabPipelineScheduleVariables = `
+  variable {
+    key = "SUITE"
+    value = "full"
+  }
+
+  variable {
+    key = "DEPLOY"
+    value = "false"
+  }
+`
+
+const testAccGitlabPipelineScheduleVariablesUpdated = `
+  variable {
+    key = "SUITE"
+    value = "smoke"
+  }
+
+  variable {
+    key = "NOTIFY"
+    value = "true"
+  }
+`
+
+func testAccGitlabPipelineScheduleConfig(rInt int, cron string, active bool, variables string) string {
+	return fmt.Sprintf(`
+resource "gitlabx_project" "foo" {
+  name = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_pipeline_schedule" "foo" {
+  project = "${gitlabx_project.foo.id}"
+  description = "Nightly build"
+  ref = "master"
+  cron = "%s"
+  active = %t
+%s}
+	`, rInt, cron, active, variables)
+}
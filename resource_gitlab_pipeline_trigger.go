@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// gitlab only serves the full token of a trigger to its owner, so the token
+// is kept as returned on creation, and only read when it is not known yet,
+// i.e. after an import.
+func resourceGitlabPipelineTrigger() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGitlabPipelineTriggerCreate,
+		Read:   resourceGitlabPipelineTriggerRead,
+		Update: resourceGitlabPipelineTriggerUpdate,
+		Delete: resourceGitlabPipelineTriggerDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabPipelineTriggerImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Description: "The ID or path with namespace of the project.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"token": {
+				Type:        schema.TypeString,
+				Description: "The token to run the pipeline of the project with.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+// pipeline triggers are imported by a composite "<project>:<trigger id>" ID,
+// where the project can be either its numeric ID or its path with namespace.
+func resourceGitlabPipelineTriggerImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	project, triggerId, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := strconv.Atoi(triggerId); err != nil {
+		return nil, fmt.Errorf("Invalid pipeline trigger ID %q in import ID %q", triggerId, d.Id())
+	}
+
+	log.Printf("[DEBUG] import gitlab pipeline trigger %s/%s", project, triggerId)
+
+	d.Set("project", project)
+	d.SetId(triggerId)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGitlabPipelineTriggerCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	options := &pipelineTriggerOptions{
+		Description: gitlab.String(d.Get("description").(string)),
+	}
+
+	trigger, err := createPipelineTrigger(client, project, options)
+	if err != nil {
+		return fmt.Errorf("Error creating pipeline trigger %q in project %s: %s", *options.Description, project, err)
+	}
+
+	d.SetId(fmt.Sprintf("%d", trigger.ID))
+	d.Set("token", trigger.Token)
+
+	return resourceGitlabPipelineTriggerRead(d, meta)
+}
+
+func resourceGitlabPipelineTriggerRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	triggerId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] read gitlab pipeline trigger %s/%d", project, triggerId)
+
+	trigger, response, err := getPipelineTrigger(client, project, triggerId)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing pipeline trigger %d from state because it no longer exists in gitlab", triggerId)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("description", trigger.Description)
+	if d.Get("token").(string) == "" {
+		d.Set("token", trigger.Token)
+	}
+	return nil
+}
+
+func resourceGitlabPipelineTriggerUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	triggerId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	if d.HasChange("description") {
+		options := &pipelineTriggerOptions{
+			Description: gitlab.String(d.Get("description").(string)),
+		}
+
+		if err := editPipelineTrigger(client, project, triggerId, options); err != nil {
+			return fmt.Errorf("Error updating pipeline trigger %d of project %s: %s", triggerId, project, err)
+		}
+	}
+
+	return resourceGitlabPipelineTriggerRead(d, meta)
+}
+
+func resourceGitlabPipelineTriggerDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	triggerId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	return deletePipelineTrigger(client, project, triggerId)
+}
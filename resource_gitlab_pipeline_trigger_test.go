@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGitlabPipelineTrigger_basic(t *testing.T) {
+	var trigger pipelineTrigger
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabPipelineTriggerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabPipelineTriggerConfig(rInt, "Downstream pipelines"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabPipelineTriggerExists("gitlabx_pipeline_trigger.foo", &trigger),
+					testAccCheckGitlabPipelineTriggerAttributes(&trigger, "Downstream pipelines"),
+					resource.TestCheckResourceAttrSet("gitlabx_pipeline_trigger.foo", "token"),
+					testAccCheckGitlabPipelineTriggerVariable("gitlabx_project_variable.foo", "gitlabx_pipeline_trigger.foo"),
+				),
+			},
+			// Update the description of the trigger
+			{
+				Config: testAccGitlabPipelineTriggerConfig(rInt, "Monorepo pipelines"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabPipelineTriggerExists("gitlabx_pipeline_trigger.foo", &trigger),
+					testAccCheckGitlabPipelineTriggerAttributes(&trigger, "Monorepo pipelines"),
+				),
+			},
+			{
+				ResourceName: "gitlabx_pipeline_trigger.foo",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["gitlabx_pipeline_trigger.foo"]
+					if !ok {
+						return "", fmt.Errorf("Not Found: gitlabx_pipeline_trigger.foo")
+					}
+					return fmt.Sprintf("%s:%s", rs.Primary.Attributes["project"], rs.Primary.ID), nil
+				},
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGitlabPipelineTrigger_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	var token string
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "projects", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabPipelineTriggerConfig(1, "Downstream pipelines"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("gitlabx_pipeline_trigger.foo", "token", regexp.MustCompile("^fake-trigger-")),
+					testAccCheckGitlabPipelineTriggerVariable("gitlabx_project_variable.foo", "gitlabx_pipeline_trigger.foo"),
+					testCheckFakeGitlabObject(f, "gitlabx_pipeline_trigger.foo", fakeObject{
+						"description": "Downstream pipelines",
+					}, "projects/%s/triggers/%s", "project", "id"),
+					func(s *terraform.State) error {
+						token = s.RootModule().Resources["gitlabx_pipeline_trigger.foo"].Primary.Attributes["token"]
+						return nil
+					},
+				),
+			},
+			// Update the description, keeping the token
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabPipelineTriggerConfig(1, "Monorepo pipelines"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckFakeGitlabObject(f, "gitlabx_pipeline_trigger.foo", fakeObject{
+						"description": "Monorepo pipelines",
+					}, "projects/%s/triggers/%s", "project", "id"),
+					func(s *terraform.State) error {
+						return resource.TestCheckResourceAttr("gitlabx_pipeline_trigger.foo", "token", token)(s)
+					},
+				),
+			},
+			{
+				ResourceName: "gitlabx_pipeline_trigger.foo",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["gitlabx_pipeline_trigger.foo"]
+					if !ok {
+						return "", fmt.Errorf("Not Found: gitlabx_pipeline_trigger.foo")
+					}
+					return fmt.Sprintf("%s:%s", rs.Primary.Attributes["project"], rs.Primary.ID), nil
+				},
+				ImportStateVerify: true,
+			},
+			// Create the trigger again after it has been deleted outside of
+			// Terraform
+			{
+				PreConfig: func() {
+					f.remove(f.find(f.find("projects", "path", "foo-1")+"/triggers", "description", "Monorepo pipelines"))
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabPipelineTriggerConfig(1, "Monorepo pipelines"),
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						return testCheckFakeGitlabCount(f, f.find("projects", "path", "foo-1")+"/triggers", 1)(s)
+					},
+					func(s *terraform.State) error {
+						if s.RootModule().Resources["gitlabx_pipeline_trigger.foo"].Primary.Attributes["token"] == token {
+							return fmt.Errorf("the token of the trigger was not renewed")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabPipelineTriggerExists(n string, trigger *pipelineTrigger) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		triggerId, err := strconv.Atoi(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		conn := testAccProvider.Meta().(*Client)
+
+		got, _, err := getPipelineTrigger(conn, rs.Primary.Attributes["project"], triggerId)
+		if err != nil {
+			return err
+		}
+		*trigger = *got
+		return nil
+	}
+}
+
+// testAccCheckGitlabPipelineTriggerVariable checks that the value of the
+// given variable is the token of the given trigger.
+func testAccCheckGitlabPipelineTriggerVariable(variable, trigger string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		v, ok := s.RootModule().Resources[variable]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", variable)
+		}
+		t, ok := s.RootModule().Resources[trigger]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", trigger)
+		}
+		if v.Primary.Attributes["value"] != t.Primary.Attributes["token"] {
+			return fmt.Errorf("the value of %s is not the token of %s", variable, trigger)
+		}
+		return nil
+	}
+}
+
+func testAccCheckGitlabPipelineTriggerAttributes(trigger *pipelineTrigger, description string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if trigger.Description != description {
+			return fmt.Errorf("got description %q; want %q", trigger.Description, description)
+		}
+		return nil
+	}
+}
+
+func testAccCheckGitlabPipelineTriggerDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gitlabx_pipeline_trigger" {
+			continue
+		}
+
+		triggerId, err := strconv.Atoi(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		_, resp, err := getPipelineTrigger(conn, rs.Primary.Attributes["project"], triggerId)
+		if err == nil {
+			return fmt.Errorf("Pipeline trigger %s still exists", rs.Primary.ID)
+		}
+		if resp == nil || resp.StatusCode != 404 {
+			return err
+		}
+	}
+	return nil
+}
+
+func testAccGitlabPipelineTriggerConfig(rInt int, description string) string {
+	return fmt.Sprintf(`
+resource "gitlabx_project" "foo" {
+  name = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_pipeline_trigger" "foo" {
+  project = "${gitlabx_project.foo.id}"
+  description = "%s"
+}
+
+resource "gitlabx_project_variable" "foo" {
+  project = "${gitlabx_project.foo.id}"
+  key = "TRIGGER_TOKEN"
+  value = "${gitlabx_pipeline_trigger.foo.token}"
+  protected = true
+}
+	`, rInt, description)
+}
@@ -18,6 +18,9 @@ func resourceGitlabProject() *schema.Resource {
 		Read:   resourceGitlabProjectRead,
 		Update: resourceGitlabProjectUpdate,
 		Delete: resourceGitlabProjectDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabProjectImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			// all these fieds can be set at creation/update time
@@ -49,6 +52,16 @@ func resourceGitlabProject() *schema.Resource {
 				Type:        schema.TypeInt,
 				Description: "Namespace for the new project; by default it's the current user's namespace, but it can\nbe the ID of a group.",
 				Optional:    true,
+				Computed:    true,
+			},
+			// alternatively, the namespace can be referenced by its full path
+			// (e.g. "team/subteam"), which is resolved into its ID
+			"namespace_path": {
+				Type:          schema.TypeString,
+				Description:   "Full path of the namespace for the new project, as an alternative to namespace_id.",
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"namespace_id"},
 			},
 			"description": {
 				Type:        schema.TypeString,
@@ -69,7 +82,7 @@ func resourceGitlabProject() *schema.Resource {
 			},
 			"builds_enabled": {
 				Type:        schema.TypeBool,
-				Description: "Enable builds for this project.",
+				Description: "Enable builds (jobs, on API v4) for this project.",
 				Optional:    true,
 				Computed:    true,
 			},
@@ -114,7 +127,7 @@ func resourceGitlabProject() *schema.Resource {
 			},
 			"public_builds": {
 				Type:        schema.TypeBool,
-				Description: "If true, builds can be viewed by non-project-members.",
+				Description: "If true, builds (jobs, on API v4) can be viewed by non-project-members.",
 				Optional:    true,
 				Computed:    true,
 			},
@@ -139,6 +152,14 @@ func resourceGitlabProject() *schema.Resource {
 				Description: "Allow users to request member access.",
 				Optional:    true,
 			},
+			// the user (ID or username) on behalf of whom the project is
+			// managed, overriding the provider-level sudo; this is how an
+			// admin can create projects owned by someone else
+			"sudo": {
+				Type:        schema.TypeString,
+				Description: "The user (ID or username) to act as when managing the project; requires an admin token.",
+				Optional:    true,
+			},
 			// all the following fields are computed, and are not stored in the
 			// Terraform state
 			"ssh_url_to_repo": {
@@ -224,6 +245,7 @@ func resourceGitlabProjectSetToState(d *schema.ResourceData, project *gitlab.Pro
 	d.Set("path", project.Path)
 	d.Set("default_branch", project.DefaultBranch)
 	d.Set("namespace_id", project.Namespace.ID)
+	d.Set("namespace_path", project.Namespace.FullPath)
 	d.Set("description", project.Description)
 	d.Set("issues_enabled", project.IssuesEnabled)
 	d.Set("merge_requests_enabled", project.MergeRequestsEnabled)
@@ -241,17 +263,36 @@ func resourceGitlabProjectSetToState(d *schema.ResourceData, project *gitlab.Pro
 	d.Set("request_access_enabled", project.RequestAccessEnabled)
 }
 
+// projects can be imported either by their numeric ID or by their path with
+// namespace (e.g. "team/app"); either way the ID in the state is the numeric
+// one.
+func resourceGitlabProjectImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*Client)
+	log.Printf("[DEBUG] import gitlab project %s", d.Id())
+
+	project, _, err := getProject(client, d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("Error importing project %s: %s", d.Id(), err)
+	}
+
+	d.SetId(fmt.Sprintf("%d", project.ID))
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceGitlabProjectExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(*gitlab.Client)
-	project, _, err := client.Projects.GetProject(d.Id)
+	client := meta.(*Client)
+	project, response, err := getProject(client, d.Id(), sudoOptions(d)...)
 	if project != nil && err == nil {
 		return true, nil
 	}
+	if response != nil && response.StatusCode == 404 {
+		return false, nil
+	}
 	return false, err
 }
 
 func resourceGitlabProjectCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gitlab.Client)
+	client := meta.(*Client)
 	options := &gitlab.CreateProjectOptions{
 		Name: gitlab.String(d.Get("name").(string)),
 	}
@@ -264,7 +305,13 @@ func resourceGitlabProjectCreate(d *schema.ResourceData, meta interface{}) error
 		options.DefaultBranch = gitlab.String(v.(string))
 	}
 
-	if v, ok := d.GetOk("namespace_id"); ok {
+	if v, ok := d.GetOk("namespace_path"); ok {
+		id, err := resolveNamespacePath(client, v.(string))
+		if err != nil {
+			return err
+		}
+		options.NamespaceID = gitlab.Int(id)
+	} else if v, ok := d.GetOk("namespace_id"); ok {
 		if _, err := checkNamespace(client, v.(int)); err != nil {
 			return err
 		}
@@ -329,7 +376,7 @@ func resourceGitlabProjectCreate(d *schema.ResourceData, meta interface{}) error
 
 	log.Printf("[DEBUG] create gitlab project %q", options.Name)
 
-	project, _, err := client.Projects.CreateProject(options)
+	project, err := createProject(client, options, sudoOptions(d)...)
 	if err != nil {
 		return err
 	}
@@ -340,10 +387,10 @@ func resourceGitlabProjectCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceGitlabProjectRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gitlab.Client)
+	client := meta.(*Client)
 	log.Printf("[DEBUG] read gitlab project %s", d.Id())
 
-	project, response, err := client.Projects.GetProject(d.Id())
+	project, response, err := getProject(client, d.Id(), sudoOptions(d)...)
 	if err != nil {
 		if response.StatusCode == 404 {
 			log.Printf("[WARN] removing project %s from state because it no longer exists in gitlab", d.Id())
@@ -359,7 +406,7 @@ func resourceGitlabProjectRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceGitlabProjectUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gitlab.Client)
+	client := meta.(*Client)
 
 	options := &gitlab.EditProjectOptions{}
 
@@ -371,7 +418,22 @@ func resourceGitlabProjectUpdate(d *schema.ResourceData, meta interface{}) error
 		options.Path = gitlab.String(d.Get("path").(string))
 	}
 
-	// the Group's namespace id is the ID of the group itself.
+	// the Group's namespace id is the ID of the group itself; moving the
+	// project into a different namespace is not an edit but a transfer, and
+	// it is performed before the other changes are applied.
+	if v, ok := d.GetOk("namespace_path"); ok && d.HasChange("namespace_path") {
+		id, err := resolveNamespacePath(client, v.(string))
+		if err != nil {
+			return err
+		}
+		if err := transferProject(client, d.Id(), id, sudoOptions(d)...); err != nil {
+			return err
+		}
+	} else if v, ok := d.GetOk("namespace_id"); ok && d.HasChange("namespace_id") {
+		if err := transferProject(client, d.Id(), v.(int), sudoOptions(d)...); err != nil {
+			return err
+		}
+	}
 
 	if d.HasChange("description") {
 		options.Description = gitlab.String(d.Get("description").(string))
@@ -431,7 +493,7 @@ func resourceGitlabProjectUpdate(d *schema.ResourceData, meta interface{}) error
 
 	log.Printf("[DEBUG] update gitlab project %s", d.Id())
 
-	_, _, err := client.Projects.EditProject(d.Id(), options)
+	err := editProject(client, d.Id(), options, sudoOptions(d)...)
 	if err != nil {
 		return err
 	}
@@ -440,9 +502,9 @@ func resourceGitlabProjectUpdate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceGitlabProjectDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gitlab.Client)
+	client := meta.(*Client)
 	log.Printf("[DEBUG] Delete gitlab project %s", d.Id())
 
-	_, err := client.Projects.DeleteProject(d.Id())
+	_, err := client.Projects.DeleteProject(d.Id(), sudoOptions(d)...)
 	return err
 }
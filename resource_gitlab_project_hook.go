@@ -15,6 +15,9 @@ func resourceGitlabProjectHook() *schema.Resource {
 		Read:   resourceGitlabProjectHookRead,
 		Update: resourceGitlabProjectHookUpdate,
 		Delete: resourceGitlabProjectHookDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabProjectHookImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"project": {
@@ -79,8 +82,26 @@ func resourceGitlabProjectHook() *schema.Resource {
 	}
 }
 
+// project hooks are imported by a composite "<project>:<hook id>" ID, where
+// the project can be either its numeric ID or its path with namespace.
+func resourceGitlabProjectHookImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	project, hookId, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := strconv.Atoi(hookId); err != nil {
+		return nil, fmt.Errorf("Invalid hook ID %q in import ID %q", hookId, d.Id())
+	}
+
+	log.Printf("[DEBUG] import gitlab project hook %s/%s", project, hookId)
+
+	d.Set("project", project)
+	d.SetId(hookId)
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceGitlabProjectHookCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gitlab.Client)
+	client := meta.(*Client)
 	project := d.Get("project").(string)
 	options := &gitlab.AddProjectHookOptions{
 		URL:                   gitlab.String(d.Get("url").(string)),
@@ -101,7 +122,7 @@ func resourceGitlabProjectHookCreate(d *schema.ResourceData, meta interface{}) e
 
 	log.Printf("[DEBUG] create gitlab project hook %q", options.URL)
 
-	hook, _, err := client.Projects.AddProjectHook(project, options)
+	hook, err := addProjectHook(client, project, options)
 	if err != nil {
 		return err
 	}
@@ -112,7 +133,7 @@ func resourceGitlabProjectHookCreate(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceGitlabProjectHookRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gitlab.Client)
+	client := meta.(*Client)
 	project := d.Get("project").(string)
 	hookId, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -120,7 +141,7 @@ func resourceGitlabProjectHookRead(d *schema.ResourceData, meta interface{}) err
 	}
 	log.Printf("[DEBUG] read gitlab project hook %s/%d", project, hookId)
 
-	hook, response, err := client.Projects.GetProjectHook(project, hookId)
+	hook, response, err := getProjectHook(client, project, hookId)
 	if err != nil {
 		if response.StatusCode == 404 {
 			log.Printf("[WARN] removing project hook %d from state because it no longer exists in gitlab", hookId)
@@ -145,7 +166,7 @@ func resourceGitlabProjectHookRead(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceGitlabProjectHookUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gitlab.Client)
+	client := meta.(*Client)
 	project := d.Get("project").(string)
 	hookId, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -170,7 +191,7 @@ func resourceGitlabProjectHookUpdate(d *schema.ResourceData, meta interface{}) e
 
 	log.Printf("[DEBUG] update gitlab project hook %s", d.Id())
 
-	_, _, err = client.Projects.EditProjectHook(project, hookId, options)
+	err = editProjectHook(client, project, hookId, options)
 	if err != nil {
 		return err
 	}
@@ -179,7 +200,7 @@ func resourceGitlabProjectHookUpdate(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceGitlabProjectHookDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gitlab.Client)
+	client := meta.(*Client)
 	project := d.Get("project").(string)
 	hookId, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -67,6 +67,33 @@ func TestAccGitlabProjectHook_basic(t *testing.T) {
 	})
 }
 
+func TestAccGitlabProjectHook_import(t *testing.T) {
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabProjectHookDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabProjectHookConfig(rInt),
+			},
+			{
+				ResourceName: "gitlabx_project_hook.foo",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["gitlabx_project_hook.foo"]
+					if !ok {
+						return "", fmt.Errorf("Not Found: gitlabx_project_hook.foo")
+					}
+					return fmt.Sprintf("%s:%s", rs.Primary.Attributes["project"], rs.Primary.ID), nil
+				},
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func testAccCheckGitlabProjectHookExists(n string, hook *gitlab.ProjectHook) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -82,9 +109,9 @@ func testAccCheckGitlabProjectHookExists(n string, hook *gitlab.ProjectHook) res
 		if repoName == "" {
 			return fmt.Errorf("No project ID is set")
 		}
-		conn := testAccProvider.Meta().(*gitlab.Client)
+		conn := testAccProvider.Meta().(*Client)
 
-		gotHook, _, err := conn.Projects.GetProjectHook(repoName, hookID)
+		gotHook, _, err := getProjectHook(conn, repoName, hookID)
 		if err != nil {
 			return err
 		}
@@ -153,14 +180,14 @@ func testAccCheckGitlabProjectHookAttributes(hook *gitlab.ProjectHook, want *tes
 }
 
 func testAccCheckGitlabProjectHookDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*gitlab.Client)
+	conn := testAccProvider.Meta().(*Client)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "gitlabx_project" {
 			continue
 		}
 
-		gotRepo, resp, err := conn.Projects.GetProject(rs.Primary.ID)
+		gotRepo, resp, err := getProject(conn, rs.Primary.ID)
 		if err == nil {
 			if gotRepo != nil && fmt.Sprintf("%d", gotRepo.ID) == rs.Primary.ID {
 				return fmt.Errorf("Repository still exists")
@@ -218,3 +245,51 @@ resource "gitlabx_project_hook" "foo" {
 }
 	`, rInt, rInt)
 }
+
+func TestGitlabProjectHook_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "projects", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectHookConfig(1),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_project_hook.foo", fakeObject{
+					"url":         "https://example.com/hook-1",
+					"push_events": true,
+				}, "projects/%s/hooks/%s", "project", "id"),
+			},
+			// Update the hook, whose job events are set on API v4
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectHookUpdateConfig(1),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_project_hook.foo", fakeObject{
+					"push_events":             false,
+					"job_events":              true,
+					"enable_ssl_verification": false,
+				}, "projects/%s/hooks/%s", "project", "id"),
+			},
+			// Revert a change made outside of Terraform
+			{
+				PreConfig: func() {
+					f.set(f.find(f.find("projects", "path", "foo-1")+"/hooks", "url", "https://example.com/hook-1"), fakeObject{"job_events": false})
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectHookUpdateConfig(1),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_project_hook.foo", fakeObject{
+					"job_events": true,
+				}, "projects/%s/hooks/%s", "project", "id"),
+			},
+			// Re-create the hook after it has been deleted outside of Terraform
+			{
+				PreConfig: func() {
+					f.remove(f.find(f.find("projects", "path", "foo-1")+"/hooks", "url", "https://example.com/hook-1"))
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectHookUpdateConfig(1),
+				Check: func(s *terraform.State) error {
+					return testCheckFakeGitlabCount(f, f.find("projects", "path", "foo-1")+"/hooks", 1)(s)
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceGitlabProjectMembers manages the whole list of members of a project:
+// unlike gitlabx_project_membership, it removes any member that has not been
+// declared, e.g. because it has been added through the UI.
+func resourceGitlabProjectMembers() *schema.Resource {
+	s := membersSchema([]string{"guest", "reporter", "developer", "master"})
+	s["project"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The ID or path with namespace of the project.",
+		Required:    true,
+		ForceNew:    true,
+	}
+
+	return &schema.Resource{
+		Create: resourceGitlabProjectMembersCreate,
+		Read:   resourceGitlabProjectMembersRead,
+		Update: resourceGitlabProjectMembersUpdate,
+		Delete: resourceGitlabProjectMembersDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabProjectMembersImport,
+		},
+
+		Schema: s,
+	}
+}
+
+// the members of a project are imported by the project ID or path with
+// namespace.
+func resourceGitlabProjectMembersImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Printf("[DEBUG] import gitlab project members %s", d.Id())
+
+	d.Set("project", d.Id())
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGitlabProjectMembersCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+
+	if err := applyMembers(client, "projects", project, d); err != nil {
+		return err
+	}
+
+	d.SetId(project)
+
+	return resourceGitlabProjectMembersRead(d, meta)
+}
+
+func resourceGitlabProjectMembersRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	log.Printf("[DEBUG] read gitlab project members %s", project)
+
+	members, response, err := listMembers(client, "projects", project)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing project members %s from state because the project no longer exists in gitlab", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	return setMembersToState(d, members)
+}
+
+func resourceGitlabProjectMembersUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+
+	if err := applyMembers(client, "projects", project, d); err != nil {
+		return err
+	}
+
+	return resourceGitlabProjectMembersRead(d, meta)
+}
+
+func resourceGitlabProjectMembersDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+
+	return removeMembers(client, "projects", project, d)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestGitlabProjectMembers_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+	developer := f.addUser("developer")
+	intruder := f.addUser("intruder")
+
+	members := func() string {
+		return f.find("projects", "path", "foo") + "/members"
+	}
+	config := testFakeGitlabConfig(f) + fmt.Sprintf(`
+resource "gitlabx_project" "foo" {
+  name = "foo"
+  visibility_level = "private"
+}
+
+resource "gitlabx_project_members" "foo" {
+  project = "${gitlabx_project.foo.id}"
+
+  member {
+    user_id = %d
+    access_level = "developer"
+  }
+}
+	`, developer)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "projects", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: func(s *terraform.State) error {
+					return testCheckFakeGitlabCount(f, members(), 1)(s)
+				},
+			},
+			{
+				ResourceName:      "gitlabx_project_members.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Remove the member added outside of Terraform
+			{
+				PreConfig: func() {
+					f.put(fmt.Sprintf("%s/%d", members(), intruder), fakeObject{"id": intruder, "username": "intruder", "access_level": 30})
+				},
+				Config: config,
+				Check: func(s *terraform.State) error {
+					if f.get(fmt.Sprintf("%s/%d", members(), intruder)) != nil {
+						return fmt.Errorf("the undeclared member has not been removed")
+					}
+					return testCheckFakeGitlabCount(f, members(), 1)(s)
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func resourceGitlabProjectMembership() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGitlabProjectMembershipCreate,
+		Read:   resourceGitlabProjectMembershipRead,
+		Update: resourceGitlabProjectMembershipUpdate,
+		Delete: resourceGitlabProjectMembershipDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabProjectMembershipImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Description: "The ID or path with namespace of the project.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"user_id": {
+				Type:        schema.TypeInt,
+				Description: "The ID of the user to add to the project.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"access_level": {
+				Type:         schema.TypeString,
+				Description:  "The role of the user in the project: one of guest, reporter, developer or master.",
+				Required:     true,
+				ValidateFunc: validateValueFunc([]string{"guest", "reporter", "developer", "master"}),
+			},
+			"expires_at": {
+				Type:         schema.TypeString,
+				Description:  "The date (in the YYYY-MM-DD format) when the membership expires; by default it never does.",
+				Optional:     true,
+				ValidateFunc: validateDate,
+			},
+		},
+	}
+}
+
+// project memberships are imported by a composite "<project>:<user id>" ID,
+// where the project can be either its numeric ID or its path with namespace.
+func resourceGitlabProjectMembershipImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	project, user, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	userID, err := strconv.Atoi(user)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid user ID %q in import ID %q", user, d.Id())
+	}
+
+	log.Printf("[DEBUG] import gitlab project membership %s/%d", project, userID)
+
+	d.Set("project", project)
+	d.Set("user_id", userID)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGitlabProjectMembershipCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	options := &memberOptions{
+		UserID:      gitlab.Int(d.Get("user_id").(int)),
+		AccessLevel: gitlab.Int(accessLevel(d.Get("access_level").(string))),
+	}
+
+	if v, ok := d.GetOk("expires_at"); ok {
+		options.ExpiresAt = gitlab.String(v.(string))
+	}
+
+	if _, err := addMember(client, "projects", project, options); err != nil {
+		return fmt.Errorf("Error adding user %d to project %s: %s", *options.UserID, project, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%d", project, *options.UserID))
+
+	return resourceGitlabProjectMembershipRead(d, meta)
+}
+
+func resourceGitlabProjectMembershipRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	user := d.Get("user_id").(int)
+	log.Printf("[DEBUG] read gitlab project membership %s", d.Id())
+
+	member, response, err := getMember(client, "projects", project, user)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing project membership %s from state because it no longer exists in gitlab", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("access_level", accessLevelNames[member.AccessLevel])
+	d.Set("expires_at", member.ExpiresAt)
+	return nil
+}
+
+func resourceGitlabProjectMembershipUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	user := d.Get("user_id").(int)
+
+	// the access level must always be given, even if unchanged
+	options := &memberOptions{
+		AccessLevel: gitlab.Int(accessLevel(d.Get("access_level").(string))),
+	}
+
+	if d.HasChange("expires_at") {
+		options.ExpiresAt = gitlab.String(d.Get("expires_at").(string))
+	}
+
+	if err := editMember(client, "projects", project, user, options); err != nil {
+		return fmt.Errorf("Error updating user %d in project %s: %s", user, project, err)
+	}
+
+	return resourceGitlabProjectMembershipRead(d, meta)
+}
+
+func resourceGitlabProjectMembershipDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	user := d.Get("user_id").(int)
+
+	return removeMember(client, "projects", project, user)
+}
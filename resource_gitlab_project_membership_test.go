@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGitlabProjectMembership_basic(t *testing.T) {
+	var m member
+	rInt := acctest.RandInt()
+	user := testAccUserID(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabProjectMembershipDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabProjectMembershipConfig(rInt, user, "reporter", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabProjectMembershipExists("gitlabx_project_membership.foo", &m),
+					testAccCheckGitlabMemberAttributes(&m, 20, ""),
+				),
+			},
+			// Change the role and set an expiration date
+			{
+				Config: testAccGitlabProjectMembershipConfig(rInt, user, "developer", "2099-12-31"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabProjectMembershipExists("gitlabx_project_membership.foo", &m),
+					testAccCheckGitlabMemberAttributes(&m, 30, "2099-12-31"),
+				),
+			},
+			{
+				ResourceName:      "gitlabx_project_membership.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGitlabProjectMembership_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+	user := f.addUser("reporter")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "projects", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectMembershipConfig(1, user, "reporter", ""),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_project_membership.foo", fakeObject{
+					"access_level": 20,
+					"expires_at":   nil,
+				}, "projects/%s/members/%s", "project", "user_id"),
+			},
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectMembershipConfig(1, user, "developer", "2099-12-31"),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_project_membership.foo", fakeObject{
+					"access_level": 30,
+					"expires_at":   "2099-12-31",
+				}, "projects/%s/members/%s", "project", "user_id"),
+			},
+			{
+				ResourceName:      "gitlabx_project_membership.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Revert a role changed outside of Terraform
+			{
+				PreConfig: func() {
+					f.set(fmt.Sprintf("%s/members/%d", f.find("projects", "path", "foo-1"), user), fakeObject{"access_level": 40})
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectMembershipConfig(1, user, "developer", "2099-12-31"),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_project_membership.foo", fakeObject{
+					"access_level": 30,
+				}, "projects/%s/members/%s", "project", "user_id"),
+			},
+			// Add the user again after it has been removed outside of Terraform
+			{
+				PreConfig: func() {
+					f.remove(fmt.Sprintf("%s/members/%d", f.find("projects", "path", "foo-1"), user))
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectMembershipConfig(1, user, "developer", ""),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_project_membership.foo", fakeObject{
+					"access_level": 30,
+					"expires_at":   nil,
+				}, "projects/%s/members/%s", "project", "user_id"),
+			},
+		},
+	})
+}
+
+func TestGitlabProjectMembership_invalidAccessLevel(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		Steps: []resource.TestStep{
+			{
+				Config:      testFakeGitlabConfig(f) + testAccGitlabProjectMembershipConfig(1, 1, "owner", ""),
+				ExpectError: regexp.MustCompile("owner is an invalid value for argument access_level"),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabProjectMembershipExists(n string, m *member) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		user, err := strconv.Atoi(rs.Primary.Attributes["user_id"])
+		if err != nil {
+			return err
+		}
+		conn := testAccProvider.Meta().(*Client)
+
+		got, _, err := getMember(conn, "projects", rs.Primary.Attributes["project"], user)
+		if err != nil {
+			return err
+		}
+		*m = *got
+		return nil
+	}
+}
+
+func testAccCheckGitlabProjectMembershipDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gitlabx_project_membership" {
+			continue
+		}
+
+		user, err := strconv.Atoi(rs.Primary.Attributes["user_id"])
+		if err != nil {
+			return err
+		}
+		_, resp, err := getMember(conn, "projects", rs.Primary.Attributes["project"], user)
+		if err == nil {
+			return fmt.Errorf("Project membership %s still exists", rs.Primary.ID)
+		}
+		if resp == nil || resp.StatusCode != 404 {
+			return err
+		}
+	}
+	return nil
+}
+
+func testAccGitlabProjectMembershipConfig(rInt, user int, accessLevel, expiresAt string) string {
+	if expiresAt != "" {
+		expiresAt = fmt.Sprintf("expires_at = %q", expiresAt)
+	}
+	return fmt.Sprintf(`
+resource "gitlabx_project" "foo" {
+  name = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_project_membership" "foo" {
+  project = "${gitlabx_project.foo.id}"
+  user_id = %d
+  access_level = "%s"
+  %s
+}
+	`, rInt, user, accessLevel, expiresAt)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func resourceGitlabProjectShareGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGitlabProjectShareGroupCreate,
+		Read:   resourceGitlabProjectShareGroupRead,
+		Delete: resourceGitlabProjectShareGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabProjectShareGroupImport,
+		},
+
+		// shares cannot be updated, only removed and added again
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Description: "The ID or path with namespace of the project.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"group_id": {
+				Type:        schema.TypeInt,
+				Description: "The ID of the group to share the project with.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"group_access": {
+				Type:         schema.TypeString,
+				Description:  "The maximum role of the members of the group in the project: one of guest, reporter, developer or master.",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateValueFunc([]string{"guest", "reporter", "developer", "master"}),
+			},
+			"expires_at": {
+				Type:         schema.TypeString,
+				Description:  "The date (in the YYYY-MM-DD format) when the share expires; by default it never does.",
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateDate,
+			},
+		},
+	}
+}
+
+// project shares are imported by a composite "<project>:<group id>" ID, where
+// the project can be either its numeric ID or its path with namespace.
+func resourceGitlabProjectShareGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	project, group, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	groupID, err := strconv.Atoi(group)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid group ID %q in import ID %q", group, d.Id())
+	}
+
+	log.Printf("[DEBUG] import gitlab project share %s/%d", project, groupID)
+
+	d.Set("project", project)
+	d.Set("group_id", groupID)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGitlabProjectShareGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	options := &shareOptions{
+		GroupID:     gitlab.Int(d.Get("group_id").(int)),
+		GroupAccess: gitlab.Int(accessLevel(d.Get("group_access").(string))),
+	}
+
+	if v, ok := d.GetOk("expires_at"); ok {
+		options.ExpiresAt = gitlab.String(v.(string))
+	}
+
+	if err := shareWithGroup(client, "projects", project, options); err != nil {
+		return fmt.Errorf("Error sharing project %s with group %d: %s", project, *options.GroupID, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%d", project, *options.GroupID))
+
+	return resourceGitlabProjectShareGroupRead(d, meta)
+}
+
+func resourceGitlabProjectShareGroupRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	group := d.Get("group_id").(int)
+	log.Printf("[DEBUG] read gitlab project share %s", d.Id())
+
+	shared, response, err := getSharedGroup(client, "projects", project, group)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing project share %s from state because the project no longer exists in gitlab", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if shared == nil {
+		log.Printf("[WARN] removing project share %s from state because it no longer exists in gitlab", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("group_access", accessLevelNames[shared.GroupAccessLevel])
+	d.Set("expires_at", shared.ExpiresAt)
+	return nil
+}
+
+func resourceGitlabProjectShareGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	group := d.Get("group_id").(int)
+
+	return unshareWithGroup(client, "projects", project, group)
+}
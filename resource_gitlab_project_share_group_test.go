@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGitlabProjectShareGroup_basic(t *testing.T) {
+	var shared sharedGroup
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabProjectShareGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabProjectShareGroupConfig(rInt, "reporter", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabProjectShareGroupExists("gitlabx_project_share_group.foo", &shared),
+					testAccCheckGitlabSharedGroupAttributes(&shared, 20, ""),
+				),
+			},
+			// Share the project again, with a different access level
+			{
+				Config: testAccGitlabProjectShareGroupConfig(rInt, "developer", "2099-12-31"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabProjectShareGroupExists("gitlabx_project_share_group.foo", &shared),
+					testAccCheckGitlabSharedGroupAttributes(&shared, 30, "2099-12-31"),
+				),
+			},
+			{
+				ResourceName:      "gitlabx_project_share_group.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGitlabProjectShareGroup_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	share := func() string {
+		return fmt.Sprintf("%s/share/%s", f.find("projects", "path", "foo-1"), strings.TrimPrefix(f.find("groups", "path", "foo-1"), "groups/"))
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "projects", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectShareGroupConfig(1, "reporter", ""),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_project_share_group.foo", fakeObject{
+					"group_access_level": 20,
+				}, "projects/%s/share/%s", "project", "group_id"),
+			},
+			{
+				ResourceName:      "gitlabx_project_share_group.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Share the project again after its access level has been changed
+			// outside of Terraform
+			{
+				PreConfig: func() {
+					f.set(share(), fakeObject{"group_access_level": 40})
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectShareGroupConfig(1, "reporter", ""),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_project_share_group.foo", fakeObject{
+					"group_access_level": 20,
+				}, "projects/%s/share/%s", "project", "group_id"),
+			},
+			// Share the project again after it has been unshared outside of
+			// Terraform
+			{
+				PreConfig: func() {
+					f.remove(share())
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectShareGroupConfig(1, "reporter", "2099-12-31"),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_project_share_group.foo", fakeObject{
+					"group_access_level": 20,
+					"expires_at":         "2099-12-31",
+				}, "projects/%s/share/%s", "project", "group_id"),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabProjectShareGroupExists(n string, shared *sharedGroup) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		group, err := strconv.Atoi(rs.Primary.Attributes["group_id"])
+		if err != nil {
+			return err
+		}
+		conn := testAccProvider.Meta().(*Client)
+
+		got, _, err := getSharedGroup(conn, "projects", rs.Primary.Attributes["project"], group)
+		if err != nil {
+			return err
+		}
+		if got == nil {
+			return fmt.Errorf("Project %s is not shared with group %d", rs.Primary.Attributes["project"], group)
+		}
+		*shared = *got
+		return nil
+	}
+}
+
+func testAccCheckGitlabSharedGroupAttributes(shared *sharedGroup, accessLevel int, expiresAt string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if shared.GroupAccessLevel != accessLevel {
+			return fmt.Errorf("got group access level %d; want %d", shared.GroupAccessLevel, accessLevel)
+		}
+		if shared.ExpiresAt != expiresAt {
+			return fmt.Errorf("got expires_at %q; want %q", shared.ExpiresAt, expiresAt)
+		}
+		return nil
+	}
+}
+
+func testAccCheckGitlabProjectShareGroupDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gitlabx_project_share_group" {
+			continue
+		}
+
+		group, err := strconv.Atoi(rs.Primary.Attributes["group_id"])
+		if err != nil {
+			return err
+		}
+		shared, resp, err := getSharedGroup(conn, "projects", rs.Primary.Attributes["project"], group)
+		if err == nil && shared != nil {
+			return fmt.Errorf("Project share %s still exists", rs.Primary.ID)
+		}
+		if err != nil && (resp == nil || resp.StatusCode != 404) {
+			return err
+		}
+	}
+	return nil
+}
+
+func testAccGitlabProjectShareGroupConfig(rInt int, groupAccess, expiresAt string) string {
+	if expiresAt != "" {
+		expiresAt = fmt.Sprintf("expires_at = %q", expiresAt)
+	}
+	return fmt.Sprintf(`
+resource "gitlabx_group" "foo" {
+  name = "foo-%d"
+  path = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_project" "foo" {
+  name = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_project_share_group" "foo" {
+  project = "${gitlabx_project.foo.id}"
+  group_id = "${gitlabx_group.foo.id}"
+  group_access = "%s"
+  %s
+}
+	`, rInt, rInt, rInt, groupAccess, expiresAt)
+}
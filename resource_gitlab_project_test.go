@@ -67,6 +67,91 @@ func TestAccGitlabProject_basic(t *testing.T) {
 	})
 }
 
+func TestAccGitlabProject_import(t *testing.T) {
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabProjectConfig(rInt),
+			},
+			// Import by numeric ID
+			{
+				ResourceName:      "gitlabx_project.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Import by path with namespace
+			{
+				ResourceName: "gitlabx_project.foo",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["gitlabx_project.foo"]
+					if !ok {
+						return "", fmt.Errorf("Not Found: gitlabx_project.foo")
+					}
+					return rs.Primary.Attributes["path_with_namespace"], nil
+				},
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccGitlabProject_transfer(t *testing.T) {
+	var project gitlab.Project
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabProjectDestroy,
+		Steps: []resource.TestStep{
+			// Create the project in the first group
+			{
+				Config: testAccGitlabProjectTransferConfig(rInt, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabProjectExists("gitlabx_project.foo", &project),
+					resource.TestCheckResourceAttrPair("gitlabx_project.foo", "namespace_id", "gitlabx_group.first", "id"),
+				),
+			},
+			// Move the project into the second group
+			{
+				Config: testAccGitlabProjectTransferConfig(rInt, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabProjectExists("gitlabx_project.foo", &project),
+					resource.TestCheckResourceAttrPair("gitlabx_project.foo", "namespace_id", "gitlabx_group.second", "id"),
+					resource.TestCheckResourceAttr("gitlabx_project.foo", "path_with_namespace", fmt.Sprintf("second-%d/foo-%d", rInt, rInt)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccGitlabProject_namespacePath(t *testing.T) {
+	var project gitlab.Project
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabProjectNamespacePathConfig(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabProjectExists("gitlabx_project.foo", &project),
+					resource.TestCheckResourceAttrPair("gitlabx_project.foo", "namespace_id", "gitlabx_group.parent", "id"),
+					resource.TestCheckResourceAttr("gitlabx_project.foo", "namespace_path", fmt.Sprintf("parent-%d", rInt)),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckGitlabProjectExists(n string, project *gitlab.Project) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -78,9 +163,9 @@ func testAccCheckGitlabProjectExists(n string, project *gitlab.Project) resource
 		if repoName == "" {
 			return fmt.Errorf("No project ID is set")
 		}
-		conn := testAccProvider.Meta().(*gitlab.Client)
+		conn := testAccProvider.Meta().(*Client)
 
-		gotProject, _, err := conn.Projects.GetProject(repoName)
+		gotProject, _, err := getProject(conn, repoName)
 		if err != nil {
 			return err
 		}
@@ -141,14 +226,14 @@ func testAccCheckGitlabProjectAttributes(project *gitlab.Project, want *testAccG
 }
 
 func testAccCheckGitlabProjectDestroy(s *terraform.State) error {
-	conn := testAccProvider.Meta().(*gitlab.Client)
+	conn := testAccProvider.Meta().(*Client)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "gitlabx_project" {
 			continue
 		}
 
-		gotRepo, resp, err := conn.Projects.GetProject(rs.Primary.ID)
+		gotRepo, resp, err := getProject(conn, rs.Primary.ID)
 		if err == nil {
 			if gotRepo != nil && fmt.Sprintf("%d", gotRepo.ID) == rs.Primary.ID {
 				return fmt.Errorf("Repository still exists")
@@ -192,3 +277,139 @@ resource "gitlabx_project" "foo" {
 }
 	`, rInt)
 }
+
+func testAccGitlabProjectTransferConfig(rInt int, group string) string {
+	return fmt.Sprintf(`
+resource "gitlabx_group" "first" {
+  name = "first-%d"
+  path = "first-%d"
+  visibility_level = "private"
+}
+
+resource "gitlabx_group" "second" {
+  name = "second-%d"
+  path = "second-%d"
+  visibility_level = "private"
+}
+
+resource "gitlabx_project" "foo" {
+  name = "foo-%d"
+  description = "Terraform acceptance tests"
+  namespace_id = "${gitlabx_group.%s.id}"
+
+  # So that acceptance tests can be run in a gitlab organization
+  # with no billing
+  visibility_level = "public"
+}
+	`, rInt, rInt, rInt, rInt, rInt, group)
+}
+
+func testAccGitlabProjectNamespacePathConfig(rInt int) string {
+	return fmt.Sprintf(`
+resource "gitlabx_group" "parent" {
+  name = "parent-%d"
+  path = "parent-%d"
+  visibility_level = "private"
+}
+
+resource "gitlabx_project" "foo" {
+  name = "foo-%d"
+  description = "Terraform acceptance tests"
+  namespace_path = "${gitlabx_group.parent.full_path}"
+
+  # So that acceptance tests can be run in a gitlab organization
+  # with no billing
+  visibility_level = "public"
+}
+	`, rInt, rInt, rInt)
+}
+
+func TestGitlabProject_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "projects", 0),
+		Steps: []resource.TestStep{
+			// Create a project in the user's namespace
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectConfig(1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlabx_project.foo", "namespace_id", "1"),
+					resource.TestCheckResourceAttr("gitlabx_project.foo", "path_with_namespace", "root/foo-1"),
+					testCheckFakeGitlabObject(f, "gitlabx_project.foo", fakeObject{
+						"description": "Terraform acceptance tests",
+						"visibility":  "public",
+					}, "projects/%s", "id"),
+				),
+			},
+			// Turn the features off
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectUpdateConfig(1),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_project.foo", fakeObject{
+					"description":            "Terraform acceptance tests!",
+					"issues_enabled":         false,
+					"merge_requests_enabled": false,
+					"wiki_enabled":           false,
+					"snippets_enabled":       false,
+				}, "projects/%s", "id"),
+			},
+			// Revert a change made outside of Terraform
+			{
+				PreConfig: func() {
+					f.set(f.find("projects", "path", "foo-1"), fakeObject{"wiki_enabled": true})
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectUpdateConfig(1),
+				Check: testCheckFakeGitlabObject(f, "gitlabx_project.foo", fakeObject{
+					"wiki_enabled": false,
+				}, "projects/%s", "id"),
+			},
+			// Re-create the project after it has been deleted outside of Terraform
+			{
+				PreConfig: func() {
+					f.remove(f.find("projects", "path", "foo-1"))
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectUpdateConfig(1),
+				Check:  testCheckFakeGitlabCount(f, "projects", 1),
+			},
+		},
+	})
+}
+
+func TestGitlabProject_fakeTransfer(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "projects", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectTransferConfig(1, "first"),
+				Check:  resource.TestCheckResourceAttr("gitlabx_project.foo", "path_with_namespace", "first-1/foo-1"),
+			},
+			// Move the project into the other group, keeping its ID
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectTransferConfig(1, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlabx_project.foo", "path_with_namespace", "second-1/foo-1"),
+					testCheckFakeGitlabCount(f, "projects", 1),
+					// transfers to groups go through the API open to group
+					// owners rather than the admin-only one
+					func(s *terraform.State) error {
+						if n := f.countRequests("PUT", "/transfer"); n != 1 {
+							return fmt.Errorf("got %d transfer requests; want 1", n)
+						}
+						return nil
+					},
+				),
+			},
+			// Reference the namespace by its path
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectNamespacePathConfig(1),
+				Check:  resource.TestCheckResourceAttr("gitlabx_project.foo", "path_with_namespace", "parent-1/foo-1"),
+			},
+		},
+	})
+}
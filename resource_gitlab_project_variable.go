@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceGitlabProjectVariable() *schema.Resource {
+	s := variableSchema()
+	s["project"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The ID or path with namespace of the project.",
+		Required:    true,
+		ForceNew:    true,
+	}
+
+	return &schema.Resource{
+		Create: resourceGitlabProjectVariableCreate,
+		Read:   resourceGitlabProjectVariableRead,
+		Update: resourceGitlabProjectVariableUpdate,
+		Delete: resourceGitlabProjectVariableDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabProjectVariableImport,
+		},
+
+		Schema: s,
+	}
+}
+
+// project variables are imported by a composite
+// "<project>:<key>:<environment scope>" ID, where the project can be either
+// its numeric ID or its path with namespace.
+func resourceGitlabProjectVariableImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	project, key, scope, err := parseVariableID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[DEBUG] import gitlab project variable %s", d.Id())
+
+	d.Set("project", project)
+	d.Set("key", key)
+	d.Set("environment_scope", scope)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGitlabProjectVariableCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	key := d.Get("key").(string)
+	scope := d.Get("environment_scope").(string)
+
+	if err := createVariable(client, "projects", project, expandVariable(variableSettings(d))); err != nil {
+		return fmt.Errorf("Error creating variable %s of project %s: %s", key, project, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s:%s", project, key, scope))
+
+	return resourceGitlabProjectVariableRead(d, meta)
+}
+
+func resourceGitlabProjectVariableRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	return readVariable(d, client, "projects", d.Get("project").(string))
+}
+
+func resourceGitlabProjectVariableUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	key := d.Get("key").(string)
+	scope := d.Get("environment_scope").(string)
+
+	if err := updateVariable(client, "projects", project, key, scope, expandVariable(variableSettings(d))); err != nil {
+		return fmt.Errorf("Error updating variable %s of project %s: %s", key, project, err)
+	}
+
+	return resourceGitlabProjectVariableRead(d, meta)
+}
+
+func resourceGitlabProjectVariableDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	return deleteVariable(client, "projects", d.Get("project").(string), d.Get("key").(string), d.Get("environment_scope").(string))
+}
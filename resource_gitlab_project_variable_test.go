@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGitlabProjectVariable_basic(t *testing.T) {
+	var v variable
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabVariableDestroy("gitlabx_project_variable", "projects", "project"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabProjectVariableConfig(rInt, "hello", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabVariableExists("gitlabx_project_variable.foo", "projects", "project", &v),
+					testAccCheckGitlabVariableAttributes(&v, "hello", false),
+					testAccCheckGitlabVariableExists("gitlabx_project_variable.production", "projects", "project", &v),
+					testAccCheckGitlabVariableAttributes(&v, "postgres://production", true),
+				),
+			},
+			// Update the value, and mask it
+			{
+				Config: testAccGitlabProjectVariableConfig(rInt, "supersecret", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabVariableExists("gitlabx_project_variable.foo", "projects", "project", &v),
+					testAccCheckGitlabVariableAttributes(&v, "supersecret", false),
+				),
+			},
+			{
+				ResourceName:      "gitlabx_project_variable.production",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGitlabProjectVariable_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	path := func(scope string) string {
+		return fmt.Sprintf("%s/variables/DB_URL:%s", f.find("projects", "path", "foo-1"), url.PathEscape(scope))
+	}
+	value := func(scope, want string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			object := f.get(path(scope))
+			if object == nil {
+				return fmt.Errorf("the %s variable does not exist", scope)
+			}
+			if object["value"] != want {
+				return fmt.Errorf("got %s value %v; want %s", scope, object["value"], want)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "projects", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectVariableConfig(1, "hello", false),
+				Check: resource.ComposeTestCheckFunc(
+					value("*", "hello"),
+					value("production", "postgres://production"),
+					resource.TestCheckResourceAttr("gitlabx_project_variable.foo", "variable_type", "env_var"),
+					resource.TestCheckResourceAttr("gitlabx_project_variable.production", "protected", "true"),
+				),
+			},
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectVariableConfig(1, "supersecret", true),
+				Check: resource.ComposeTestCheckFunc(
+					value("*", "supersecret"),
+					func(s *terraform.State) error {
+						if f.get(path("*"))["masked"] != true {
+							return fmt.Errorf("the variable has not been masked")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "gitlabx_project_variable.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update the variable again after it has been changed outside of
+			// Terraform
+			{
+				PreConfig: func() {
+					f.set(path("*"), fakeObject{"value": "changed", "protected": true})
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectVariableConfig(1, "supersecret", true),
+				Check: resource.ComposeTestCheckFunc(
+					value("*", "supersecret"),
+					func(s *terraform.State) error {
+						if f.get(path("*"))["protected"] != false {
+							return fmt.Errorf("the variable is still protected")
+						}
+						return nil
+					},
+				),
+			},
+			// Create the variable again after it has been deleted outside of
+			// Terraform
+			{
+				PreConfig: func() {
+					f.remove(path("production"))
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectVariableConfig(1, "supersecret", true),
+				Check:  value("production", "postgres://production"),
+			},
+			{
+				Config:      testFakeGitlabConfig(f) + testAccGitlabProjectVariableConfig(1, "short", true),
+				ExpectError: regexp.MustCompile(`value: \[is invalid\]`),
+			},
+			{
+				Config: testFakeGitlabConfig(f) + `
+resource "gitlabx_project_variable" "invalid" {
+  project = "foo"
+  key = "DB-URL"
+  value = "hello"
+  variable_type = "yaml"
+}
+				`,
+				ExpectError: regexp.MustCompile(`"DB-URL" is an invalid key`),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabVariableExists(n, kind, attr string, v *variable) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*Client)
+
+		got, _, err := getVariable(conn, kind, rs.Primary.Attributes[attr], rs.Primary.Attributes["key"], rs.Primary.Attributes["environment_scope"])
+		if err != nil {
+			return err
+		}
+		*v = *got
+		return nil
+	}
+}
+
+func testAccCheckGitlabVariableAttributes(v *variable, value string, protected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if v.Value != value {
+			return fmt.Errorf("got value %q; want %q", v.Value, value)
+		}
+		if v.Protected != protected {
+			return fmt.Errorf("got protected %t; want %t", v.Protected, protected)
+		}
+		return nil
+	}
+}
+
+// testAccCheckGitlabVariableDestroy checks that the variables of the given
+// type, owned by projects or groups as given by kind and attr, are gone.
+func testAccCheckGitlabVariableDestroy(resourceType, kind, attr string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn := testAccProvider.Meta().(*Client)
+
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+
+			_, resp, err := getVariable(conn, kind, rs.Primary.Attributes[attr], rs.Primary.Attributes["key"], rs.Primary.Attributes["environment_scope"])
+			if err == nil {
+				return fmt.Errorf("Variable %s still exists", rs.Primary.ID)
+			}
+			if resp == nil || resp.StatusCode != 404 {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func testAccGitlabProjectVariableConfig(rInt int, value string, masked bool) string {
+	return fmt.Sprintf(`
+resource "gitlabx_project" "foo" {
+  name = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_project_variable" "foo" {
+  project = "${gitlabx_project.foo.id}"
+  key = "DB_URL"
+  value = "%s"
+  masked = %t
+}
+
+resource "gitlabx_project_variable" "production" {
+  project = "${gitlabx_project.foo.id}"
+  key = "DB_URL"
+  value = "postgres://production"
+  protected = true
+  environment_scope = "production"
+}
+	`, rInt, value, masked)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceGitlabProjectVariables manages the whole set of variables of a
+// project, for an environment scope, from a map of keys to values: unlike
+// gitlabx_project_variable, it deletes any variable that has not been
+// declared, unless told to leave them alone.
+func resourceGitlabProjectVariables() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGitlabProjectVariablesCreate,
+		Read:   resourceGitlabProjectVariablesRead,
+		Update: resourceGitlabProjectVariablesUpdate,
+		Delete: resourceGitlabProjectVariablesDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabProjectVariablesImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Description: "The ID or path with namespace of the project.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"variables": {
+				Type:        schema.TypeMap,
+				Description: "The values of the variables, by key.",
+				Optional:    true,
+				Sensitive:   true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"environment_scope": {
+				Type:        schema.TypeString,
+				Description: "The environments the variables are set for (e.g. production or review/*); the variables of other environments are left alone.",
+				Optional:    true,
+				Default:     "*",
+				ForceNew:    true,
+			},
+			"ignore_undeclared": {
+				Type:        schema.TypeBool,
+				Description: "Leave alone the variables that were never declared, rather than deleting them; those removed from variables are deleted regardless.",
+				Optional:    true,
+				Default:     false,
+			},
+		},
+	}
+}
+
+// the variables of a project are imported by a composite
+// "<project>:<environment scope>" ID, or by the project ID or path with
+// namespace alone for the variables of the "*" environment scope.
+func resourceGitlabProjectVariablesImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	log.Printf("[DEBUG] import gitlab project variables %s", d.Id())
+
+	project, scope := d.Id(), "*"
+	if parts := strings.SplitN(d.Id(), ":", 2); len(parts) == 2 {
+		project, scope = parts[0], parts[1]
+	}
+
+	d.Set("project", project)
+	d.Set("environment_scope", scope)
+	d.Set("ignore_undeclared", false)
+	d.SetId(project + ":" + scope)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGitlabProjectVariablesCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+
+	if err := applyVariables(client, "projects", project, d); err != nil {
+		return err
+	}
+
+	d.SetId(project + ":" + d.Get("environment_scope").(string))
+
+	return resourceGitlabProjectVariablesRead(d, meta)
+}
+
+func resourceGitlabProjectVariablesRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	log.Printf("[DEBUG] read gitlab project variables %s", d.Id())
+
+	variables, response, err := listVariables(client, "projects", project)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing project variables %s from state because the project no longer exists in gitlab", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	return setVariablesToState(d, variables)
+}
+
+func resourceGitlabProjectVariablesUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+
+	if err := applyVariables(client, "projects", project, d); err != nil {
+		return err
+	}
+
+	return resourceGitlabProjectVariablesRead(d, meta)
+}
+
+func resourceGitlabProjectVariablesDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+
+	return deleteVariables(client, "projects", project, d)
+}
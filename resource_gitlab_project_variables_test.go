@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGitlabProjectVariables_basic(t *testing.T) {
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabProjectVariablesConfig(rInt, "2", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gitlabx_project_variables.foo", "variables.%", "2"),
+					resource.TestCheckResourceAttr("gitlabx_project_variables.foo", "variables.REGISTRY_USER", "deployer"),
+				),
+			},
+			{
+				Config: testAccGitlabProjectVariablesConfig(rInt, "3", false),
+				Check:  resource.TestCheckResourceAttr("gitlabx_project_variables.foo", "variables.REGISTRY_PASSWORD", "3"),
+			},
+			{
+				ResourceName:      "gitlabx_project_variables.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGitlabProjectVariables_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	path := func(key string) string {
+		return fmt.Sprintf("%s/variables/%s:%s", f.find("projects", "path", "foo-1"), key, url.PathEscape("*"))
+	}
+	values := func(want map[string]string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			if n := f.count(f.find("projects", "path", "foo-1") + "/variables"); n != len(want) {
+				return fmt.Errorf("got %d variables; want %d", n, len(want))
+			}
+			for key, value := range want {
+				if object := f.get(path(key)); object == nil || object["value"] != value {
+					return fmt.Errorf("got variable %s %v; want %s", key, object, value)
+				}
+			}
+			return nil
+		}
+	}
+	undeclared := func() {
+		f.put(path("UNDECLARED"), fakeObject{
+			"key":               "UNDECLARED",
+			"value":             "added through the UI",
+			"variable_type":     "env_var",
+			"protected":         false,
+			"masked":            false,
+			"environment_scope": "*",
+		})
+	}
+	// the number of requests creating, updating and deleting variables
+	var writes [3]int
+	countWrites := func() [3]int {
+		return [3]int{f.countRequests("POST", "/variables"), f.countRequests("PUT", "/variables"), f.countRequests("DELETE", "/variables")}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "projects", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectVariablesConfig(1, "2", false),
+				Check: values(map[string]string{
+					"REGISTRY_USER":     "deployer",
+					"REGISTRY_PASSWORD": "2",
+				}),
+			},
+			// Update a single variable, and delete the undeclared one
+			{
+				PreConfig: func() {
+					undeclared()
+					writes = countWrites()
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectVariablesConfig(1, "3", false),
+				Check: resource.ComposeTestCheckFunc(
+					values(map[string]string{
+						"REGISTRY_USER":     "deployer",
+						"REGISTRY_PASSWORD": "3",
+					}),
+					func(s *terraform.State) error {
+						got := countWrites()
+						if want := [3]int{writes[0], writes[1] + 1, writes[2] + 1}; got != want {
+							return fmt.Errorf("got %v creations, updates and deletions; want %v", got, want)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "gitlabx_project_variables.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Leave the undeclared variable alone
+			{
+				PreConfig: undeclared,
+				Config:    testFakeGitlabConfig(f) + testAccGitlabProjectVariablesConfig(1, "3", true),
+				Check: resource.ComposeTestCheckFunc(
+					values(map[string]string{
+						"REGISTRY_USER":     "deployer",
+						"REGISTRY_PASSWORD": "3",
+						"UNDECLARED":        "added through the UI",
+					}),
+					resource.TestCheckResourceAttr("gitlabx_project_variables.foo", "variables.%", "2"),
+				),
+			},
+			// Update the variable again after it has been changed outside of
+			// Terraform
+			{
+				PreConfig: func() {
+					f.set(path("REGISTRY_USER"), fakeObject{"value": "changed"})
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectVariablesConfig(1, "3", true),
+				Check: values(map[string]string{
+					"REGISTRY_USER":     "deployer",
+					"REGISTRY_PASSWORD": "3",
+					"UNDECLARED":        "added through the UI",
+				}),
+			},
+			// Delete the variable that is no longer declared, while still
+			// leaving the undeclared one alone
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabProjectVariablesConfig(1, "", true),
+				Check: resource.ComposeTestCheckFunc(
+					values(map[string]string{
+						"REGISTRY_USER": "deployer",
+						"UNDECLARED":    "added through the UI",
+					}),
+					resource.TestCheckResourceAttr("gitlabx_project_variables.foo", "variables.%", "1"),
+				),
+			},
+		},
+	})
+}
+
+// testAccGitlabProjectVariablesConfig declares the REGISTRY_PASSWORD variable
+// unless the password is empty.
+func testAccGitlabProjectVariablesConfig(rInt int, password string, ignoreUndeclared bool) string {
+	variables := `    REGISTRY_USER = "deployer"`
+	if password != "" {
+		variables += fmt.Sprintf(`
+    REGISTRY_PASSWORD = "%s"`, password)
+	}
+	return fmt.Sprintf(`
+resource "gitlabx_project" "foo" {
+  name = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_project_variables" "foo" {
+  project = "${gitlabx_project.foo.id}"
+  ignore_undeclared = %t
+
+  variables = {
+%s
+  }
+}
+	`, rInt, ignoreUndeclared, variables)
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// A repository commit is a set of changes to the files of a branch, applied
+// at once; commits cannot be changed once made, so any change to the resource
+// results in a new commit, and destroying it only removes it from the state.
+// The actions are applied to the files as they are in the branch when
+// committing (e.g. a file that already exists is updated rather than
+// created), and the commit is made again when the files no longer have the
+// content it gave them.
+func resourceGitlabRepositoryCommit() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGitlabRepositoryCommitCreate,
+		Read:   resourceGitlabRepositoryCommitRead,
+		Delete: resourceGitlabRepositoryCommitDelete,
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Description: "The ID or path with namespace of the project.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"branch": {
+				Type:        schema.TypeString,
+				Description: "The branch to commit to; in an empty repository, or when start_branch is set, it is created.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"start_branch": {
+				Type:        schema.TypeString,
+				Description: "The branch or commit to start from, if the branch does not exist yet.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"commit_message": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"author_email": {
+				Type:        schema.TypeString,
+				Description: "The email address of the author of the commit; by default, the authenticated user's.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"author_name": {
+				Type:        schema.TypeString,
+				Description: "The name of the author of the commit; by default, the authenticated user's.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"action": {
+				Type:        schema.TypeList,
+				Description: "The changes to the files, in the order they are applied.",
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"action": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: validateValueFunc([]string{"create", "update", "delete", "move"}),
+						},
+						"file_path": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"previous_path": {
+							Type:        schema.TypeString,
+							Description: "The original path of a moved file.",
+							Optional:    true,
+							ForceNew:    true,
+						},
+						"content": {
+							Type:        schema.TypeString,
+							Description: "The content of a created or updated file; moved files keep their content if not set.",
+							Optional:    true,
+							ForceNew:    true,
+						},
+					},
+				},
+			},
+			"sha": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"short_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// expandCommitActions returns the actions in the given list of actions.
+func expandCommitActions(list []interface{}) ([]*commitAction, error) {
+	actions := []*commitAction{}
+	for _, v := range list {
+		m := v.(map[string]interface{})
+		action := &commitAction{
+			Action:   gitlab.String(m["action"].(string)),
+			FilePath: gitlab.String(m["file_path"].(string)),
+		}
+
+		previous := m["previous_path"].(string)
+		if *action.Action == "move" && previous == "" {
+			return nil, fmt.Errorf("previous_path is required to move %s", *action.FilePath)
+		}
+		if previous != "" {
+			action.PreviousPath = gitlab.String(previous)
+		}
+
+		// an empty content is a valid content for created and updated files
+		content := m["content"].(string)
+		if *action.Action == "create" || *action.Action == "update" || content != "" {
+			action.Content = gitlab.String(content)
+		}
+
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// commitFiles tracks the content of the files of a branch while actions are
+// applied to it, fetching them from gitlab the first time they are looked at;
+// a nil content means that the file does not exist.
+type commitFiles struct {
+	client  *Client
+	project string
+	ref     string
+	files   map[string]*string
+}
+
+func newCommitFiles(client *Client, project, ref string) *commitFiles {
+	return &commitFiles{
+		client:  client,
+		project: project,
+		ref:     ref,
+		files:   map[string]*string{},
+	}
+}
+
+// get returns the content of the file at the given path, or nil if there is
+// none.
+func (c *commitFiles) get(path string) (*string, error) {
+	if content, ok := c.files[path]; ok || c.ref == "" {
+		return content, nil
+	}
+
+	file, response, err := getFile(c.client, c.project, path, c.ref)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			c.files[path] = nil
+			return nil, nil
+		}
+		return nil, err
+	}
+	content, err := file.decodedContent()
+	if err != nil {
+		return nil, err
+	}
+	c.files[path] = &content
+	return &content, nil
+}
+
+// write returns the action setting the content of the file at the given
+// path, creating or updating it depending on whether it exists, or nil if it
+// already has that content.
+func (c *commitFiles) write(path, content string) (*commitAction, error) {
+	current, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+	c.files[path] = &content
+	switch {
+	case current == nil:
+		return &commitAction{Action: gitlab.String("create"), FilePath: gitlab.String(path), Content: gitlab.String(content)}, nil
+	case *current != content:
+		return &commitAction{Action: gitlab.String("update"), FilePath: gitlab.String(path), Content: gitlab.String(content)}, nil
+	}
+	return nil, nil
+}
+
+// remove returns the action deleting the file at the given path, or nil if
+// it does not exist.
+func (c *commitFiles) remove(path string) (*commitAction, error) {
+	current, err := c.get(path)
+	if err != nil || current == nil {
+		return nil, err
+	}
+	c.files[path] = nil
+	return &commitAction{Action: gitlab.String("delete"), FilePath: gitlab.String(path)}, nil
+}
+
+// adaptCommitActions returns the actions making the files of the branch end
+// up as the given actions would from the files they were written against:
+// files are created or updated depending on whether they exist, and the
+// actions that are already applied are left out.
+func adaptCommitActions(files *commitFiles, actions []*commitAction) ([]*commitAction, error) {
+	adapted := []*commitAction{}
+	add := func(action *commitAction, err error) error {
+		if action != nil {
+			adapted = append(adapted, action)
+		}
+		return err
+	}
+
+	for _, action := range actions {
+		path := *action.FilePath
+		switch *action.Action {
+		case "create", "update":
+			if err := add(files.write(path, *action.Content)); err != nil {
+				return nil, err
+			}
+		case "delete":
+			if err := add(files.remove(path)); err != nil {
+				return nil, err
+			}
+		case "move":
+			previous, err := files.get(*action.PreviousPath)
+			if err != nil {
+				return nil, err
+			}
+			current, err := files.get(path)
+			if err != nil {
+				return nil, err
+			}
+			if previous != nil && current == nil {
+				content := *previous
+				if action.Content != nil {
+					content = *action.Content
+				}
+				files.files[path] = &content
+				files.files[*action.PreviousPath] = nil
+				adapted = append(adapted, action)
+				continue
+			}
+			// the file has already been moved, or replaced
+			content := action.Content
+			if content == nil {
+				content = previous
+			}
+			if content != nil {
+				if err := add(files.write(path, *content)); err != nil {
+					return nil, err
+				}
+			}
+			if err := add(files.remove(*action.PreviousPath)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return adapted, nil
+}
+
+// committedContents returns the content the given actions give to the files
+// they touch, by path, or nil for the files they delete; the content of the
+// files moved without setting it is not known, and they are left out.
+func committedContents(actions []*commitAction) map[string]*string {
+	contents := map[string]*string{}
+	for _, action := range actions {
+		path := *action.FilePath
+		switch *action.Action {
+		case "create", "update":
+			contents[path] = action.Content
+		case "delete":
+			contents[path] = nil
+		case "move":
+			content, known := action.Content, action.Content != nil
+			if !known {
+				content, known = contents[*action.PreviousPath]
+				known = known && content != nil
+			}
+			if known {
+				contents[path] = content
+			} else {
+				delete(contents, path)
+			}
+			contents[*action.PreviousPath] = nil
+		}
+	}
+	return contents
+}
+
+func resourceGitlabRepositoryCommitCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	branch := d.Get("branch").(string)
+
+	actions, err := expandCommitActions(d.Get("action").([]interface{}))
+	if err != nil {
+		return err
+	}
+
+	options := &createCommitOptions{
+		Branch:        gitlab.String(branch),
+		CommitMessage: gitlab.String(d.Get("commit_message").(string)),
+	}
+
+	ref := branch
+	_, response, err := getBranch(client, project, branch)
+	if err != nil {
+		if response == nil || response.StatusCode != 404 {
+			return err
+		}
+		// the branch is created from start_branch, or from scratch in an
+		// empty repository
+		ref = d.Get("start_branch").(string)
+	}
+
+	if v, ok := d.GetOk("start_branch"); ok {
+		options.StartBranch = gitlab.String(v.(string))
+	}
+
+	options.Actions, err = adaptCommitActions(newCommitFiles(client, project, ref), actions)
+	if err != nil {
+		return err
+	}
+	if len(options.Actions) == 0 {
+		return fmt.Errorf("Error committing to branch %s of project %s: the branch already has the changes of the commit", branch, project)
+	}
+
+	if v, ok := d.GetOk("author_email"); ok {
+		options.AuthorEmail = gitlab.String(v.(string))
+	}
+
+	if v, ok := d.GetOk("author_name"); ok {
+		options.AuthorName = gitlab.String(v.(string))
+	}
+
+	commit, err := createCommit(client, project, options)
+	if err != nil {
+		return fmt.Errorf("Error committing to branch %s of project %s: %s", branch, project, err)
+	}
+
+	d.SetId(commit.ID)
+
+	return resourceGitlabRepositoryCommitRead(d, meta)
+}
+
+func resourceGitlabRepositoryCommitRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	log.Printf("[DEBUG] read gitlab commit %s of project %s", d.Id(), project)
+
+	commit, response, err := getCommit(client, project, d.Id())
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing commit %s from state because it no longer exists in gitlab", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	actions, err := expandCommitActions(d.Get("action").([]interface{}))
+	if err != nil {
+		return err
+	}
+	files := newCommitFiles(client, project, d.Get("branch").(string))
+	for path, want := range committedContents(actions) {
+		got, err := files.get(path)
+		if err != nil {
+			return err
+		}
+		if (got == nil) != (want == nil) || (got != nil && *got != *want) {
+			log.Printf("[WARN] removing commit %s from state because %s has changed since in gitlab", d.Id(), path)
+			d.SetId("")
+			return nil
+		}
+	}
+
+	d.Set("sha", commit.ID)
+	d.Set("short_id", commit.ShortID)
+	return nil
+}
+
+func resourceGitlabRepositoryCommitDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[DEBUG] commit %s of project %s cannot be deleted, removing it from state only", d.Id(), d.Get("project"))
+	return nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGitlabRepositoryCommit_basic(t *testing.T) {
+	var commit repositoryCommit
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabRepositoryCommitConfig(rInt, testAccGitlabRepositoryCommitCreate),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabRepositoryCommitExists("gitlabx_repository_commit.foo", &commit),
+					resource.TestCheckResourceAttrSet("gitlabx_repository_commit.foo", "sha"),
+				),
+			},
+			// Change the files in a new commit
+			{
+				Config: testAccGitlabRepositoryCommitConfig(rInt, testAccGitlabRepositoryCommitUpdate),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabRepositoryCommitExists("gitlabx_repository_commit.foo", &commit),
+					testAccCheckGitlabRepositoryCommitParent(&commit),
+				),
+			},
+		},
+	})
+}
+
+func TestGitlabRepositoryCommit_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	project := func() string {
+		return f.find("projects", "path", "foo-1")
+	}
+	files := func(want map[string]string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			for path, content := range want {
+				got, ok := f.file(project(), "master", path)
+				if content == "" && ok {
+					return fmt.Errorf("%s has not been deleted", path)
+				}
+				if content != "" && got != content {
+					return fmt.Errorf("got %s content %q; want %q", path, got, content)
+				}
+			}
+			return nil
+		}
+	}
+	commits := func(n int) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			return testCheckFakeGitlabCount(f, project()+"/repository/commits", n)(s)
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "projects", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabRepositoryCommitConfig(1, testAccGitlabRepositoryCommitCreate),
+				Check: resource.ComposeTestCheckFunc(
+					files(map[string]string{"README.md": "hello\n", "docs/index.md": "# Docs\n"}),
+					commits(1),
+					resource.TestCheckResourceAttrSet("gitlabx_repository_commit.foo", "sha"),
+					resource.TestCheckResourceAttrSet("gitlabx_repository_commit.foo", "short_id"),
+				),
+			},
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabRepositoryCommitConfig(1, testAccGitlabRepositoryCommitUpdate),
+				Check: resource.ComposeTestCheckFunc(
+					files(map[string]string{"README.md": "hello, world\n", "docs/index.md": "", "docs/README.md": "# Docs\n"}),
+					commits(2),
+				),
+			},
+			// Create the files again, updating those that already exist
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabRepositoryCommitConfig(1, testAccGitlabRepositoryCommitCreate),
+				Check: resource.ComposeTestCheckFunc(
+					files(map[string]string{"README.md": "hello\n", "docs/index.md": "# Docs\n", "docs/README.md": "# Docs\n"}),
+					commits(3),
+				),
+			},
+			// Change the content of an already committed file
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabRepositoryCommitConfig(1, strings.Replace(testAccGitlabRepositoryCommitCreate, `hello\n`, `hello again\n`, 1)),
+				Check: resource.ComposeTestCheckFunc(
+					files(map[string]string{"README.md": "hello again\n", "docs/index.md": "# Docs\n"}),
+					commits(4),
+				),
+			},
+			// Commit again after the files have been changed outside of
+			// Terraform
+			{
+				PreConfig: func() {
+					f.push(project(), "master", fakeAction{action: "update", filePath: "README.md", content: "changed\n"})
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabRepositoryCommitConfig(1, strings.Replace(testAccGitlabRepositoryCommitCreate, `hello\n`, `hello again\n`, 1)),
+				Check: resource.ComposeTestCheckFunc(
+					files(map[string]string{"README.md": "hello again\n", "docs/index.md": "# Docs\n"}),
+					commits(6),
+				),
+			},
+			// Fail to commit changes that the branch already has
+			{
+				PreConfig: func() {
+					f.push(project(), "master", fakeAction{action: "update", filePath: "README.md", content: "hello\n"})
+				},
+				Config:      testFakeGitlabConfig(f) + testAccGitlabRepositoryCommitConfig(1, testAccGitlabRepositoryCommitCreate),
+				ExpectError: regexp.MustCompile("the branch already has the changes of the commit"),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabRepositoryCommitExists(n string, commit *repositoryCommit) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*Client)
+
+		got, _, err := getCommit(conn, rs.Primary.Attributes["project"], rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		*commit = *got
+		return nil
+	}
+}
+
+func testAccCheckGitlabRepositoryCommitParent(commit *repositoryCommit) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if len(commit.ParentIDs) != 1 {
+			return fmt.Errorf("got parents %v; want the previous commit", commit.ParentIDs)
+		}
+		return nil
+	}
+}
+
+const testAccGitlabRepositoryCommitCreate = `
+  action {
+    action = "create"
+    file_path = "README.md"
+    content = "hello\n"
+  }
+
+  action {
+    action = "create"
+    file_path = "docs/index.md"
+    content = "# Docs\n"
+  }
+`
+
+const testAccGitlabRepositoryCommitUpdate = `
+  action {
+    action = "update"
+    file_path = "README.md"
+    content = "hello, world\n"
+  }
+
+  action {
+    action = "move"
+    file_path = "docs/README.md"
+    previous_path = "docs/index.md"
+  }
+`
+
+func testAccGitlabRepositoryCommitConfig(rInt int, actions string) string {
+	return fmt.Sprintf(`
+resource "gitlabx_project" "foo" {
+  name = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_repository_commit" "foo" {
+  project = "${gitlabx_project.foo.id}"
+  branch = "master"
+  commit_message = "Terraform acceptance tests"
+%s}
+	`, rInt, actions)
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func resourceGitlabRepositoryFile() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGitlabRepositoryFileCreate,
+		Read:   resourceGitlabRepositoryFileRead,
+		Update: resourceGitlabRepositoryFileUpdate,
+		Delete: resourceGitlabRepositoryFileDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabRepositoryFileImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Description: "The ID or path with namespace of the project.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"branch": {
+				Type:        schema.TypeString,
+				Description: "The branch to commit the file to; it must exist, unless the repository is empty, in which case gitlab creates it with the first commit.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"file_path": {
+				Type:        schema.TypeString,
+				Description: "The path of the file in the repository.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Description: "The content of the file.",
+				Required:    true,
+			},
+			// the following fields only affect the commits made by Terraform,
+			// and are never read back from gitlab
+			"commit_message": {
+				Type:        schema.TypeString,
+				Description: "The message of the commits; by default it describes the change (e.g. \"Update README.md\").",
+				Optional:    true,
+			},
+			"author_email": {
+				Type:        schema.TypeString,
+				Description: "The email address of the author of the commits; by default, the authenticated user's.",
+				Optional:    true,
+			},
+			"author_name": {
+				Type:        schema.TypeString,
+				Description: "The name of the author of the commits; by default, the authenticated user's.",
+				Optional:    true,
+			},
+			"content_sha256": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"last_commit_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// repository files are imported by a composite "<project>:<branch>:<file path>"
+// ID, where the project can be either its numeric ID or its path with
+// namespace.
+func resourceGitlabRepositoryFileImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("Unexpected ID format (%q), expected <project>:<branch>:<file path>", d.Id())
+	}
+
+	log.Printf("[DEBUG] import gitlab repository file %s", d.Id())
+
+	d.Set("project", parts[0])
+	d.Set("branch", parts[1])
+	d.Set("file_path", parts[2])
+	return []*schema.ResourceData{d}, nil
+}
+
+// repositoryFileOptions returns the options to create, update or delete the
+// file, with the given default commit message.
+func repositoryFileOptions(d *schema.ResourceData, message string) *fileOptions {
+	options := &fileOptions{
+		Branch:        gitlab.String(d.Get("branch").(string)),
+		CommitMessage: gitlab.String(message),
+	}
+
+	if v, ok := d.GetOk("commit_message"); ok {
+		options.CommitMessage = gitlab.String(v.(string))
+	}
+
+	if v, ok := d.GetOk("author_email"); ok {
+		options.AuthorEmail = gitlab.String(v.(string))
+	}
+
+	if v, ok := d.GetOk("author_name"); ok {
+		options.AuthorName = gitlab.String(v.(string))
+	}
+
+	return options
+}
+
+func resourceGitlabRepositoryFileCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	branch := d.Get("branch").(string)
+	path := d.Get("file_path").(string)
+
+	options := repositoryFileOptions(d, fmt.Sprintf("Create %s", path))
+	options.Content = gitlab.String(d.Get("content").(string))
+
+	if err := writeFile(client, "POST", project, path, options); err != nil {
+		return fmt.Errorf("Error creating file %s in branch %s of project %s: %s", path, branch, project, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s:%s", project, branch, path))
+
+	return resourceGitlabRepositoryFileRead(d, meta)
+}
+
+func resourceGitlabRepositoryFileRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	branch := d.Get("branch").(string)
+	path := d.Get("file_path").(string)
+	log.Printf("[DEBUG] read gitlab repository file %s", d.Id())
+
+	file, response, err := getFile(client, project, path, branch)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing repository file %s from state because it no longer exists in gitlab", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	// the content is only replaced when it has actually changed, which is
+	// cheaper to tell by comparing hashes
+	hash := file.ContentSHA256
+	if content, ok := d.GetOk("content"); !ok || hash == "" || hash != contentSHA256(content.(string)) {
+		content, err := file.decodedContent()
+		if err != nil {
+			return err
+		}
+		d.Set("content", content)
+		hash = contentSHA256(content)
+	}
+
+	d.Set("content_sha256", hash)
+	d.Set("last_commit_id", file.LastCommitID)
+	return nil
+}
+
+func resourceGitlabRepositoryFileUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	branch := d.Get("branch").(string)
+	path := d.Get("file_path").(string)
+
+	// a new commit is only needed when the content changes
+	if d.HasChange("content") {
+		options := repositoryFileOptions(d, fmt.Sprintf("Update %s", path))
+		options.Content = gitlab.String(d.Get("content").(string))
+
+		if err := writeFile(client, "PUT", project, path, options); err != nil {
+			return fmt.Errorf("Error updating file %s in branch %s of project %s: %s", path, branch, project, err)
+		}
+	}
+
+	return resourceGitlabRepositoryFileRead(d, meta)
+}
+
+func resourceGitlabRepositoryFileDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	path := d.Get("file_path").(string)
+
+	options := repositoryFileOptions(d, fmt.Sprintf("Delete %s", path))
+	return writeFile(client, "DELETE", project, path, options)
+}
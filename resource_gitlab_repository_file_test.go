@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGitlabRepositoryFile_basic(t *testing.T) {
+	var file repositoryFile
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabRepositoryFileDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabRepositoryFileConfig(rInt, "hello\n"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabRepositoryFileExists("gitlabx_repository_file.foo", &file),
+					testAccCheckGitlabRepositoryFileContent(&file, "hello\n"),
+				),
+			},
+			// Update the content of the file
+			{
+				Config: testAccGitlabRepositoryFileConfig(rInt, "hello, world\n"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabRepositoryFileExists("gitlabx_repository_file.foo", &file),
+					testAccCheckGitlabRepositoryFileContent(&file, "hello, world\n"),
+				),
+			},
+			{
+				ResourceName:            "gitlabx_repository_file.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"commit_message", "author_email", "author_name"},
+			},
+		},
+	})
+}
+
+func TestGitlabRepositoryFile_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	project := func() string {
+		return f.find("projects", "path", "foo-1")
+	}
+	content := func(want string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			got, ok := f.file(project(), "master", "docs/README.md")
+			if !ok {
+				return fmt.Errorf("the file does not exist")
+			}
+			if got != want {
+				return fmt.Errorf("got content %q; want %q", got, want)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "projects", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabRepositoryFileConfig(1, "hello\n"),
+				Check: resource.ComposeTestCheckFunc(
+					content("hello\n"),
+					resource.TestCheckResourceAttr("gitlabx_repository_file.foo", "content_sha256", contentSHA256("hello\n")),
+					resource.TestCheckResourceAttrSet("gitlabx_repository_file.foo", "last_commit_id"),
+					resource.TestCheckResourceAttr("gitlabx_repository_file.spaced", "file_path", "docs/my file.md"),
+					func(s *terraform.State) error {
+						if got, ok := f.file(project(), "master", "docs/my file.md"); !ok || got != "spaced\n" {
+							return fmt.Errorf("got docs/my file.md %q; want %q", got, "spaced\n")
+						}
+						if _, ok := f.file(project(), "master", "docs/my+file.md"); ok {
+							return fmt.Errorf("docs/my file.md was created as docs/my+file.md")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabRepositoryFileConfig(1, "hello, world\n"),
+				Check: resource.ComposeTestCheckFunc(
+					content("hello, world\n"),
+					resource.TestCheckResourceAttr("gitlabx_repository_file.foo", "content_sha256", contentSHA256("hello, world\n")),
+				),
+			},
+			{
+				ResourceName:            "gitlabx_repository_file.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"commit_message", "author_email", "author_name"},
+			},
+			// Update the file again after it has been changed outside of
+			// Terraform
+			{
+				PreConfig: func() {
+					f.push(project(), "master", fakeAction{action: "update", filePath: "docs/README.md", content: "changed\n"})
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabRepositoryFileConfig(1, "hello, world\n"),
+				Check:  content("hello, world\n"),
+			},
+			// Create the file again after it has been deleted outside of
+			// Terraform
+			{
+				PreConfig: func() {
+					f.push(project(), "master", fakeAction{action: "delete", filePath: "docs/README.md"})
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabRepositoryFileConfig(1, "hello, world\n"),
+				Check:  content("hello, world\n"),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabRepositoryFileExists(n string, file *repositoryFile) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*Client)
+
+		got, _, err := getFile(conn, rs.Primary.Attributes["project"], rs.Primary.Attributes["file_path"], rs.Primary.Attributes["branch"])
+		if err != nil {
+			return err
+		}
+		*file = *got
+		return nil
+	}
+}
+
+func testAccCheckGitlabRepositoryFileContent(file *repositoryFile, want string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		got, err := file.decodedContent()
+		if err != nil {
+			return err
+		}
+		if got != want {
+			return fmt.Errorf("got content %q; want %q", got, want)
+		}
+		return nil
+	}
+}
+
+func testAccCheckGitlabRepositoryFileDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gitlabx_repository_file" {
+			continue
+		}
+
+		_, resp, err := getFile(conn, rs.Primary.Attributes["project"], rs.Primary.Attributes["file_path"], rs.Primary.Attributes["branch"])
+		if err == nil {
+			return fmt.Errorf("Repository file %s still exists", rs.Primary.ID)
+		}
+		if resp == nil || resp.StatusCode != 404 {
+			return err
+		}
+	}
+	return nil
+}
+
+func testAccGitlabRepositoryFileConfig(rInt int, content string) string {
+	return fmt.Sprintf(`
+resource "gitlabx_project" "foo" {
+  name = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_repository_file" "foo" {
+  project = "${gitlabx_project.foo.id}"
+  branch = "master"
+  file_path = "docs/README.md"
+  content = %q
+  commit_message = "Terraform acceptance tests"
+}
+
+resource "gitlabx_repository_file" "spaced" {
+  project = "${gitlabx_project.foo.id}"
+  branch = "master"
+  file_path = "docs/my file.md"
+  content = "spaced\n"
+  commit_message = "Terraform acceptance tests"
+  depends_on = ["gitlabx_repository_file.foo"]
+}
+	`, rInt, content)
+}
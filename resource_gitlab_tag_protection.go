@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func resourceGitlabTagProtection() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGitlabTagProtectionCreate,
+		Read:   resourceGitlabTagProtectionRead,
+		Delete: resourceGitlabTagProtectionDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGitlabTagProtectionImport,
+		},
+
+		// protected tags cannot be updated, only unprotected and protected
+		// again
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Description: "The ID or path with namespace of the project.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"tag": {
+				Type:        schema.TypeString,
+				Description: "The name of the tag, or a wildcard matching several tags (e.g. \"v*\").",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"create_access_level": {
+				Type:         schema.TypeString,
+				Description:  "Who can create the tag: one of no one, developer or master.",
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "master",
+				ValidateFunc: validateValueFunc([]string{"no one", "developer", "master"}),
+			},
+		},
+	}
+}
+
+// protected tags are imported by a composite "<project>:<tag>" ID, where the
+// project can be either its numeric ID or its path with namespace.
+func resourceGitlabTagProtectionImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	project, tag, err := parseTwoPartID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[DEBUG] import gitlab protected tag %s/%s", project, tag)
+
+	d.Set("project", project)
+	d.Set("tag", tag)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceGitlabTagProtectionCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	tag := d.Get("tag").(string)
+	options := &protectTagOptions{
+		Name:              gitlab.String(tag),
+		CreateAccessLevel: gitlab.Int(protectionAccessLevel(d.Get("create_access_level").(string))),
+	}
+
+	if err := protectTag(client, project, options); err != nil {
+		return fmt.Errorf("Error protecting tag %s of project %s: %s", tag, project, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", project, tag))
+
+	return resourceGitlabTagProtectionRead(d, meta)
+}
+
+func resourceGitlabTagProtectionRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	tag := d.Get("tag").(string)
+	log.Printf("[DEBUG] read gitlab protected tag %s", d.Id())
+
+	protected, response, err := getProtectedTag(client, project, tag)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing protected tag %s from state because it no longer exists in gitlab", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	level, _ := flattenAllowances(protected.CreateAccessLevels)
+	d.Set("create_access_level", level)
+	return nil
+}
+
+func resourceGitlabTagProtectionDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+	project := d.Get("project").(string)
+	tag := d.Get("tag").(string)
+
+	return unprotectTag(client, project, tag)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGitlabTagProtection_basic(t *testing.T) {
+	var protected protectedTag
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGitlabTagProtectionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGitlabTagProtectionConfig(rInt, "master"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabTagProtectionExists("gitlabx_tag_protection.foo", &protected),
+					testAccCheckGitlabTagProtectionAttributes(&protected, 40),
+				),
+			},
+			// Protect the tags again with a different access level
+			{
+				Config: testAccGitlabTagProtectionConfig(rInt, "developer"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGitlabTagProtectionExists("gitlabx_tag_protection.foo", &protected),
+					testAccCheckGitlabTagProtectionAttributes(&protected, 30),
+				),
+			},
+			{
+				ResourceName:      "gitlabx_tag_protection.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGitlabTagProtection_fake(t *testing.T) {
+	f := newFakeGitlab(t)
+	defer f.Close()
+
+	protected := func() string {
+		return fmt.Sprintf("%s/protected_tags/%s", f.find("projects", "path", "foo-1"), url.PathEscape("v*"))
+	}
+	createAccessLevel := func(level int) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			object := f.get(protected())
+			if object == nil {
+				return fmt.Errorf("the tags have not been protected")
+			}
+			if create := object["create_access_levels"].([]fakeObject); create[0]["access_level"] != level {
+				return fmt.Errorf("got create access levels %v; want %d", create, level)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    map[string]terraform.ResourceProvider{"gitlabx": Provider()},
+		CheckDestroy: testCheckFakeGitlabCount(f, "projects", 0),
+		Steps: []resource.TestStep{
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabTagProtectionConfig(1, "master"),
+				Check:  createAccessLevel(40),
+			},
+			{
+				ResourceName:      "gitlabx_tag_protection.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testFakeGitlabConfig(f) + testAccGitlabTagProtectionConfig(1, "no one"),
+				Check:  createAccessLevel(0),
+			},
+			// Protect the tags again after their access level has been
+			// changed outside of Terraform
+			{
+				PreConfig: func() {
+					f.set(protected(), fakeObject{
+						"create_access_levels": []fakeObject{{"access_level": 30}},
+					})
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabTagProtectionConfig(1, "no one"),
+				Check:  createAccessLevel(0),
+			},
+			// Protect the tags again after they have been unprotected outside
+			// of Terraform
+			{
+				PreConfig: func() {
+					f.remove(protected())
+				},
+				Config: testFakeGitlabConfig(f) + testAccGitlabTagProtectionConfig(1, "no one"),
+				Check:  createAccessLevel(0),
+			},
+			{
+				Config:      testFakeGitlabConfig(f) + testAccGitlabTagProtectionConfig(1, "admin"),
+				ExpectError: regexp.MustCompile("admin is an invalid value for argument create_access_level"),
+			},
+		},
+	})
+}
+
+func testAccCheckGitlabTagProtectionExists(n string, protected *protectedTag) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not Found: %s", n)
+		}
+
+		conn := testAccProvider.Meta().(*Client)
+
+		got, _, err := getProtectedTag(conn, rs.Primary.Attributes["project"], rs.Primary.Attributes["tag"])
+		if err != nil {
+			return err
+		}
+		*protected = *got
+		return nil
+	}
+}
+
+func testAccCheckGitlabTagProtectionAttributes(protected *protectedTag, create int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if len(protected.CreateAccessLevels) == 0 || protected.CreateAccessLevels[0].AccessLevel != create {
+			return fmt.Errorf("got create access levels %v; want %d", protected.CreateAccessLevels, create)
+		}
+		return nil
+	}
+}
+
+func testAccCheckGitlabTagProtectionDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gitlabx_tag_protection" {
+			continue
+		}
+
+		_, resp, err := getProtectedTag(conn, rs.Primary.Attributes["project"], rs.Primary.Attributes["tag"])
+		if err == nil {
+			return fmt.Errorf("Protected tag %s still exists", rs.Primary.ID)
+		}
+		if resp == nil || resp.StatusCode != 404 {
+			return err
+		}
+	}
+	return nil
+}
+
+func testAccGitlabTagProtectionConfig(rInt int, create string) string {
+	return fmt.Sprintf(`
+resource "gitlabx_project" "foo" {
+  name = "foo-%d"
+  description = "Terraform acceptance tests"
+  visibility_level = "private"
+}
+
+resource "gitlabx_tag_protection" "foo" {
+  project = "${gitlabx_project.foo.id}"
+  tag = "v*"
+  create_access_level = "%s"
+}
+	`, rInt, create)
+}
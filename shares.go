@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// Projects and groups can be shared with other groups, whose members are then
+// granted access up to the given level; both are done through the same API,
+// under either projects/:id/share or groups/:id/share, and the groups a
+// project or group is shared with are listed in its details. The kind
+// argument is either "projects" or "groups".
+
+// sharedGroup is a group a project or group is shared with.
+type sharedGroup struct {
+	GroupID          int    `json:"group_id"`
+	GroupName        string `json:"group_name"`
+	GroupFullPath    string `json:"group_full_path"`
+	GroupAccessLevel int    `json:"group_access_level"`
+	ExpiresAt        string `json:"expires_at"`
+}
+
+// shareOptions represents the available options when sharing a project or
+// group with a group.
+type shareOptions struct {
+	GroupID     *int    `url:"group_id,omitempty" json:"group_id,omitempty"`
+	GroupAccess *int    `url:"group_access,omitempty" json:"group_access,omitempty"`
+	ExpiresAt   *string `url:"expires_at,omitempty" json:"expires_at,omitempty"`
+}
+
+// getSharedGroup returns the share of a project or group with the given
+// group, or nil if it is not shared with it.
+func getSharedGroup(client *Client, kind, id string, group int, options ...gitlab.OptionFunc) (*sharedGroup, *gitlab.Response, error) {
+	details := &struct {
+		SharedWithGroups []*sharedGroup `json:"shared_with_groups"`
+	}{}
+	response, err := doRequest(client, "GET", fmt.Sprintf("%s/%s", kind, pathEscape(id)), nil, details, options...)
+	if err != nil {
+		return nil, response, err
+	}
+	for _, shared := range details.SharedWithGroups {
+		if shared.GroupID == group {
+			return shared, response, nil
+		}
+	}
+	return nil, response, nil
+}
+
+func shareWithGroup(client *Client, kind, id string, opt *shareOptions, options ...gitlab.OptionFunc) error {
+	log.Printf("[DEBUG] share gitlab %s %s with group %d", kind, id, *opt.GroupID)
+
+	_, err := doRequest(client, "POST", fmt.Sprintf("%s/%s/share", kind, pathEscape(id)), opt, nil, options...)
+	return err
+}
+
+func unshareWithGroup(client *Client, kind, id string, group int, options ...gitlab.OptionFunc) error {
+	log.Printf("[DEBUG] stop sharing gitlab %s %s with group %d", kind, id, group)
+
+	_, err := doRequest(client, "DELETE", fmt.Sprintf("%s/%s/share/%d", kind, pathEscape(id), group), nil, nil, options...)
+	return err
+}
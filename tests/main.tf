@@ -11,7 +11,7 @@ variable "project" {
  */
 provider "gitlabx" {
 #	token			= "<token goes here>"
-	base_url 		= "http://localhost/api/v3/"
+	base_url 		= "http://localhost"
 }
 
 /*
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// the delay before the first retry, doubled at each further attempt
+	defaultMinBackoff = 1 * time.Second
+	// the longest the transport will ever wait before retrying
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// retryTransport is an http.RoundTripper that retries the requests failing
+// because of transient errors (network errors, 5xx responses from a gateway,
+// rate limiting) with exponential backoff, honouring the Retry-After and
+// RateLimit-* headers sent by gitlab; it also throttles the outgoing requests
+// to the configured number of requests per second.
+type retryTransport struct {
+	transport  http.RoundTripper
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	limiter    *rateLimiter
+}
+
+func newRetryTransport(transport http.RoundTripper, maxRetries int, requestsPerSecond int) *retryTransport {
+	return &retryTransport{
+		transport:  transport,
+		maxRetries: maxRetries,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+		limiter:    newRateLimiter(requestsPerSecond),
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Body != nil && request.GetBody == nil {
+		// go-gitlab sets the body of its requests without GetBody, so the body
+		// is buffered to be sent again on retries
+		body, err := ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		buffered := new(http.Request)
+		*buffered = *request
+		buffered.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+		buffered.Body, _ = buffered.GetBody()
+		request = buffered
+	}
+
+	for attempt := 0; ; attempt++ {
+		current := request
+		if attempt > 0 && request.GetBody != nil {
+			body, err := request.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			current = new(http.Request)
+			*current = *request
+			current.Body = body
+		}
+
+		t.limiter.wait()
+		response, err := t.transport.RoundTrip(current)
+		if response != nil {
+			t.limiter.update(response, t.maxBackoff)
+		}
+
+		if attempt >= t.maxRetries || !retryable(request, response, err) {
+			return response, err
+		}
+
+		delay := t.backoff(attempt, response)
+		if err != nil {
+			log.Printf("[WARN] %s %s failed (%s), retrying in %s", request.Method, request.URL, err, delay)
+		} else {
+			log.Printf("[WARN] %s %s returned %s, retrying in %s", request.Method, request.URL, response.Status, delay)
+			// drain the body so that the connection can be reused
+			io.Copy(ioutil.Discard, response.Body)
+			response.Body.Close()
+		}
+		time.Sleep(delay)
+	}
+}
+
+// retryable returns whether the request can be safely sent again: requests
+// that were rejected by the rate limiter have not been processed, so they
+// can always be retried, whereas requests failing with a network error or a
+// gateway error may or may not have been processed, so only idempotent ones
+// are.
+func retryable(request *http.Request, response *http.Response, err error) bool {
+	if response != nil && response.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	switch request.Method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+	default:
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+	switch response.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns how long to wait before the given retry: if gitlab tells
+// when to retry, through the Retry-After or the RateLimit-Reset headers, that
+// is honoured, up to the longest backoff; otherwise the delay grows
+// exponentially, with some jitter.
+func (t *retryTransport) backoff(attempt int, response *http.Response) time.Duration {
+	if response != nil {
+		if delay, ok := retryAfter(response); ok {
+			if delay > t.maxBackoff {
+				delay = t.maxBackoff
+			}
+			return delay
+		}
+	}
+
+	delay := t.minBackoff << uint(attempt)
+	if delay <= 0 || delay > t.maxBackoff {
+		delay = t.maxBackoff
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// retryAfter returns how long gitlab asks to wait before retrying, if it does
+// through the Retry-After header or, when rate limiting, the RateLimit-Reset
+// header.
+func retryAfter(response *http.Response) (time.Duration, bool) {
+	if v := response.Header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(v); err == nil {
+			return time.Until(date), true
+		}
+	}
+	if reset, ok := rateLimitReset(response); ok && response.StatusCode == http.StatusTooManyRequests {
+		return time.Until(reset), true
+	}
+	return 0, false
+}
+
+// rateLimitReset returns when the rate limit window resets, as advertised by
+// gitlab in the RateLimit-Reset header (a Unix timestamp).
+func rateLimitReset(response *http.Response) (time.Time, bool) {
+	v := response.Header.Get("RateLimit-Reset")
+	if v == "" {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+// rateLimiter spaces the outgoing requests so that no more than the given
+// number of requests per second are sent, and holds them back altogether
+// when gitlab reports that the rate limit has been exhausted.
+type rateLimiter struct {
+	sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(requestsPerSecond int) *rateLimiter {
+	limiter := &rateLimiter{}
+	if requestsPerSecond > 0 {
+		limiter.interval = time.Second / time.Duration(requestsPerSecond)
+	}
+	return limiter
+}
+
+// wait blocks until the next request can be sent.
+func (l *rateLimiter) wait() {
+	l.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// update takes into account the RateLimit-* headers of a response: once no
+// requests are left in the current window, the following ones are held back
+// until the window resets, or for the given longest wait at most.
+func (l *rateLimiter) update(response *http.Response, maxWait time.Duration) {
+	if response.Header.Get("RateLimit-Remaining") != "0" {
+		return
+	}
+	reset, ok := rateLimitReset(response)
+	if !ok {
+		return
+	}
+	if limit := time.Now().Add(maxWait); reset.After(limit) {
+		reset = limit
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	if reset.After(l.next) {
+		log.Printf("[WARN] gitlab rate limit exhausted, holding requests back until %s", reset)
+		l.next = reset
+	}
+}
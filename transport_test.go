@@ -0,0 +1,290 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func testRetryTransport(maxRetries int) *retryTransport {
+	return &retryTransport{
+		transport:  http.DefaultTransport,
+		maxRetries: maxRetries,
+		minBackoff: time.Millisecond,
+		maxBackoff: 10 * time.Millisecond,
+		limiter:    newRateLimiter(0),
+	}
+}
+
+func TestRetryTransport_retries(t *testing.T) {
+	cases := []struct {
+		Name       string
+		Method     string
+		Statuses   []int
+		Header     http.Header
+		MaxRetries int
+		Calls      int
+		Status     int
+	}{
+		{
+			Name:       "transient errors on GET",
+			Method:     "GET",
+			Statuses:   []int{502, 503, 200},
+			MaxRetries: 3,
+			Calls:      3,
+			Status:     200,
+		},
+		{
+			Name:       "too many transient errors on GET",
+			Method:     "GET",
+			Statuses:   []int{502, 502, 502, 200},
+			MaxRetries: 2,
+			Calls:      3,
+			Status:     502,
+		},
+		{
+			Name:       "transient error on PUT",
+			Method:     "PUT",
+			Statuses:   []int{504, 200},
+			MaxRetries: 3,
+			Calls:      2,
+			Status:     200,
+		},
+		{
+			Name:       "transient error on POST",
+			Method:     "POST",
+			Statuses:   []int{502, 201},
+			MaxRetries: 3,
+			Calls:      1,
+			Status:     502,
+		},
+		{
+			Name:       "rate limited POST",
+			Method:     "POST",
+			Statuses:   []int{429, 201},
+			Header:     http.Header{"Retry-After": []string{"0"}},
+			MaxRetries: 3,
+			Calls:      2,
+			Status:     201,
+		},
+		{
+			Name:       "client error",
+			Method:     "GET",
+			Statuses:   []int{404, 200},
+			MaxRetries: 3,
+			Calls:      1,
+			Status:     404,
+		},
+	}
+
+	for _, tc := range cases {
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for k, v := range tc.Header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(tc.Statuses[calls])
+			calls++
+		}))
+
+		request, err := http.NewRequest(tc.Method, server.URL, strings.NewReader("payload"))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		response, err := testRetryTransport(tc.MaxRetries).RoundTrip(request)
+		server.Close()
+		if err != nil {
+			t.Fatalf("%s - unexpected error: %s", tc.Name, err)
+		}
+		if response.StatusCode != tc.Status {
+			t.Fatalf("%s - got status %d expected %d", tc.Name, response.StatusCode, tc.Status)
+		}
+		if calls != tc.Calls {
+			t.Fatalf("%s - got %d calls expected %d", tc.Name, calls, tc.Calls)
+		}
+	}
+}
+
+func TestRetryTransport_body(t *testing.T) {
+	bodies := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buffer := make([]byte, 64)
+		n, _ := r.Body.Read(buffer)
+		bodies = append(bodies, string(buffer[:n]))
+		if len(bodies) == 1 {
+			w.WriteHeader(502)
+		}
+	}))
+	defer server.Close()
+
+	request, err := http.NewRequest("PUT", server.URL, strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := testRetryTransport(3).RoundTrip(request); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(bodies) != 2 || bodies[0] != "payload" || bodies[1] != "payload" {
+		t.Fatalf("got bodies %q expected the payload to be sent twice", bodies)
+	}
+}
+
+// go-gitlab sets the body of its requests without GetBody, unlike
+// http.NewRequest.
+func TestRetryTransport_gitlabRequest(t *testing.T) {
+	bodies := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(429)
+			return
+		}
+		w.WriteHeader(201)
+		fmt.Fprint(w, `{"id": 1, "name": "foo"}`)
+	}))
+	defer server.Close()
+
+	client := gitlab.NewClient(&http.Client{Transport: testRetryTransport(3)}, "token")
+	if err := client.SetBaseURL(server.URL + "/api/v4/"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	request, err := client.NewRequest("POST", "projects", &gitlab.CreateProjectOptions{Name: gitlab.String("foo")}, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	project := &gitlab.Project{}
+	if _, err := client.Do(request, project); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if project.ID != 1 {
+		t.Fatalf("got project %d expected 1", project.ID)
+	}
+	if len(bodies) != 2 || bodies[0] == "" || bodies[1] != bodies[0] {
+		t.Fatalf("got bodies %q expected the payload to be sent twice", bodies)
+	}
+}
+
+func TestRetryTransport_backoff(t *testing.T) {
+	transport := testRetryTransport(3)
+	transport.minBackoff = time.Second
+	transport.maxBackoff = 4 * time.Second
+
+	cases := []struct {
+		Name     string
+		Attempt  int
+		Response *http.Response
+		Min      time.Duration
+		Max      time.Duration
+	}{
+		{
+			Name:    "first attempt",
+			Attempt: 0,
+			Min:     500 * time.Millisecond,
+			Max:     time.Second,
+		},
+		{
+			Name:    "second attempt",
+			Attempt: 1,
+			Min:     time.Second,
+			Max:     2 * time.Second,
+		},
+		{
+			Name:    "capped",
+			Attempt: 10,
+			Min:     2 * time.Second,
+			Max:     4 * time.Second,
+		},
+		{
+			Name:    "Retry-After",
+			Attempt: 0,
+			Response: &http.Response{
+				StatusCode: 429,
+				Header:     http.Header{"Retry-After": []string{"3"}},
+			},
+			Min: 3 * time.Second,
+			Max: 3 * time.Second,
+		},
+		{
+			Name:    "Retry-After capped",
+			Attempt: 0,
+			Response: &http.Response{
+				StatusCode: 429,
+				Header:     http.Header{"Retry-After": []string{"3600"}},
+			},
+			Min: 4 * time.Second,
+			Max: 4 * time.Second,
+		},
+		{
+			Name:    "RateLimit-Reset",
+			Attempt: 0,
+			Response: &http.Response{
+				StatusCode: 429,
+				Header:     http.Header{"Ratelimit-Reset": []string{fmt.Sprintf("%d", time.Now().Add(3*time.Second).Unix())}},
+			},
+			Min: time.Second,
+			Max: 3 * time.Second,
+		},
+		{
+			Name:    "RateLimit-Reset capped",
+			Attempt: 0,
+			Response: &http.Response{
+				StatusCode: 429,
+				Header:     http.Header{"Ratelimit-Reset": []string{fmt.Sprintf("%d", time.Now().Add(time.Minute).Unix())}},
+			},
+			Min: 4 * time.Second,
+			Max: 4 * time.Second,
+		},
+	}
+	for _, tc := range cases {
+		delay := transport.backoff(tc.Attempt, tc.Response)
+		if delay < tc.Min || delay > tc.Max {
+			t.Fatalf("%s - got delay %s expected between %s and %s", tc.Name, delay, tc.Min, tc.Max)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(20)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		limiter.wait()
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Fatalf("3 requests at 20 requests per second took %s, expected at least 100ms", elapsed)
+	}
+
+	limiter = newRateLimiter(0)
+	limiter.update(&http.Response{
+		Header: http.Header{
+			"Ratelimit-Remaining": []string{"0"},
+			"Ratelimit-Reset":     []string{fmt.Sprintf("%d", time.Now().Add(2*time.Second).Unix())},
+		},
+	}, 10*time.Second)
+	start = time.Now()
+	limiter.wait()
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("request sent after %s, expected it to be held back until the rate limit reset", elapsed)
+	}
+
+	// a far-future reset only holds requests back for the longest wait
+	limiter = newRateLimiter(0)
+	limiter.update(&http.Response{
+		Header: http.Header{
+			"Ratelimit-Remaining": []string{"0"},
+			"Ratelimit-Reset":     []string{fmt.Sprintf("%d", time.Now().Add(24*time.Hour).Unix())},
+		},
+	}, 100*time.Millisecond)
+	start = time.Now()
+	limiter.wait()
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("request sent after %s, expected it to be held back for 100ms at most", elapsed)
+	}
+}
@@ -1,10 +1,17 @@
 package main
 
 import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
 	"fmt"
 	"log"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	gitlab "github.com/xanzy/go-gitlab"
@@ -41,6 +48,133 @@ func validatePath(v interface{}, k string) (we []string, errors []error) {
 	return
 }
 
+// A date (e.g. the expiration date of a membership) must be in the
+// YYYY-MM-DD format.
+func validateDate(v interface{}, k string) (we []string, errors []error) {
+	value := v.(string)
+	if _, err := time.Parse("2006-01-02", value); err != nil {
+		errors = append(errors, fmt.Errorf("%q is an invalid %s: it must be a date in the YYYY-MM-DD format", value, k))
+	}
+	return
+}
+
+// An SSH public key must be in the OpenSSH format (e.g. "ssh-ed25519
+// AAAAC3Nza... user@host"), with a type matching the one encoded in the key.
+func validateSSHKey(v interface{}, k string) (we []string, errors []error) {
+	if _, err := sshKeyFingerprint(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%s is an invalid SSH public key: %s", k, err))
+	}
+	return
+}
+
+// sshKeyFingerprint returns the MD5 fingerprint of an SSH public key in the
+// OpenSSH format (e.g. "6b:a8:d9:61:..."), which is how gitlab tells keys
+// apart regardless of their comment.
+func sshKeyFingerprint(key string) (string, error) {
+	fields := strings.Fields(key)
+	if len(fields) < 2 {
+		return "", fmt.Errorf("expected \"<type> <base64 key> [comment]\"")
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return "", fmt.Errorf("the key is not valid base64")
+	}
+	// the key starts with its type, prefixed by its length
+	if len(blob) < 4 {
+		return "", fmt.Errorf("the key is truncated")
+	}
+	n := binary.BigEndian.Uint32(blob)
+	if uint64(len(blob)) < 4+uint64(n) || string(blob[4:4+n]) != fields[0] {
+		return "", fmt.Errorf("the key is not of type %s", fields[0])
+	}
+
+	hash := md5.Sum(blob)
+	parts := make([]string, len(hash))
+	for i, b := range hash {
+		parts[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(parts, ":"), nil
+}
+
+// the fields of a cron expression, with their ranges and, for months and days
+// of the week, the names their values can be given by
+var cronFields = []struct {
+	name     string
+	min, max int
+	names    []string
+}{
+	{"minute", 0, 59, nil},
+	{"hour", 0, 23, nil},
+	{"day of month", 1, 31, nil},
+	{"month", 1, 12, []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{"day of week", 0, 7, []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// A cron expression (e.g. "0 1 * * 1-5") is made of five fields: minute,
+// hour, day of month, month and day of week; each field is a comma-separated
+// list of values, ranges ("1-5") or wildcards ("*"), possibly with a step
+// ("*/15").
+func validateCron(v interface{}, k string) (we []string, errors []error) {
+	value := v.(string)
+	fields := strings.Fields(value)
+	if len(fields) != len(cronFields) {
+		errors = append(errors, fmt.Errorf("%q is an invalid %s: expected 5 fields (minute, hour, day of month, month and day of week)", value, k))
+		return
+	}
+
+	for i, field := range fields {
+		if err := validateCronField(field, cronFields[i].min, cronFields[i].max, cronFields[i].names); err != nil {
+			errors = append(errors, fmt.Errorf("%q is an invalid %s: invalid %s %q: %s", value, k, cronFields[i].name, field, err))
+		}
+	}
+	return
+}
+
+// validateCronField validates a field of a cron expression, whose values must
+// be in the given range or, if any, among the given names.
+func validateCronField(field string, min, max int, names []string) error {
+	value := func(s string) (int, error) {
+		for i, name := range names {
+			if strings.ToUpper(s) == name {
+				return min + i, nil
+			}
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < min || n > max {
+			return 0, fmt.Errorf("%s is not between %d and %d", s, min, max)
+		}
+		return n, nil
+	}
+
+	for _, item := range strings.Split(field, ",") {
+		parts := strings.SplitN(item, "/", 2)
+		if len(parts) == 2 {
+			if step, err := strconv.Atoi(parts[1]); err != nil || step < 1 {
+				return fmt.Errorf("%s is not a valid step", parts[1])
+			}
+		}
+		if parts[0] == "*" {
+			continue
+		}
+
+		bounds := strings.SplitN(parts[0], "-", 2)
+		from, err := value(bounds[0])
+		if err != nil {
+			return err
+		}
+		if len(bounds) == 2 {
+			to, err := value(bounds[1])
+			if err != nil {
+				return err
+			}
+			if from > to {
+				return fmt.Errorf("%s is not a valid range", parts[0])
+			}
+		}
+	}
+	return nil
+}
+
 func validateRegexpFunc(regexp string) schema.SchemaValidateFunc {
 	return func(v interface{}, k string) (we []string, errors []error) {
 		value := v.(string)
@@ -103,18 +237,218 @@ func visibilityLevelToString(v gitlab.VisibilityLevelValue) *string {
 // the project should be moved (i.e. assigned) to that user (this
 // should happen through the CreateProjectForUser API and its options); if
 // the namespace ID does not xist, there is an error in the plan
-func checkNamespace(client *gitlab.Client, id int) (string, error) {
-	namespaces, _, err := client.Namespaces.ListNamespaces(&gitlab.ListNamespacesOptions{})
+func checkNamespace(client *Client, id int) (string, error) {
+	namespace, err := client.namespaces.byID(client, id)
+	if err != nil {
+		return "", err
+	}
+	log.Printf("[DEBUG] Namespace with ID found, of type: %s", namespace.Kind)
+	return namespace.Kind, nil
+}
 
+// resolveNamespacePath returns the ID of the namespace with the given full
+// path (e.g. "team/subteam"), so that configurations need not hardcode it.
+func resolveNamespacePath(client *Client, path string) (int, error) {
+	namespace, err := client.namespaces.byPath(client, path)
 	if err != nil {
-		return "", fmt.Errorf("Error getting list of namespaces: %s", err)
+		return 0, err
 	}
+	log.Printf("[DEBUG] Namespace with path %q found, with ID: %d", path, namespace.ID)
+	return namespace.ID, nil
+}
+
+// namespacePerPage is the page size used when scanning namespaces.
+const namespacePerPage = 100
+
+// namespaceCache keeps track of the namespaces seen so far, so that resolving
+// the namespace of many projects does not require listing all the namespaces
+// of the gitlab instance over and over again; there is one per provider.
+type namespaceCache struct {
+	sync.Mutex
+	ids   map[int]*gitlab.Namespace
+	paths map[string]*gitlab.Namespace
+}
+
+func newNamespaceCache() *namespaceCache {
+	return &namespaceCache{
+		ids:   map[int]*gitlab.Namespace{},
+		paths: map[string]*gitlab.Namespace{},
+	}
+}
 
-	for _, namespace := range namespaces {
-		if namespace.ID == id {
-			log.Printf("[DEBUG] Namespace with ID found, of type: %s", namespace.Kind)
-			return namespace.Kind, nil
+func (c *namespaceCache) add(namespace *gitlab.Namespace) {
+	c.ids[namespace.ID] = namespace
+	c.paths[namespace.FullPath] = namespace
+}
+
+// byID looks the namespace up in the cache first, then scans the namespaces
+// page by page (caching everything it sees) until the namespace is found.
+func (c *namespaceCache) byID(client *Client, id int) (*gitlab.Namespace, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if namespace, ok := c.ids[id]; ok {
+		return namespace, nil
+	}
+
+	options := &gitlab.ListNamespacesOptions{
+		ListOptions: gitlab.ListOptions{
+			Page:    1,
+			PerPage: namespacePerPage,
+		},
+	}
+	for {
+		namespaces, response, err := client.Namespaces.ListNamespaces(options)
+		if err != nil {
+			return nil, fmt.Errorf("Error getting list of namespaces: %s", err)
+		}
+		for _, namespace := range namespaces {
+			c.add(namespace)
+		}
+		if namespace, ok := c.ids[id]; ok {
+			return namespace, nil
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		options.Page = response.NextPage
+	}
+	return nil, fmt.Errorf("Invalid namespace ID: %d", id)
+}
+
+// byPath looks the namespace up in the cache first, then searches gitlab for
+// namespaces matching the last element of the path, which is much cheaper
+// than scanning them all.
+func (c *namespaceCache) byPath(client *Client, path string) (*gitlab.Namespace, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	path = strings.Trim(path, "/")
+	if namespace, ok := c.paths[path]; ok {
+		return namespace, nil
+	}
+
+	options := &gitlab.ListNamespacesOptions{
+		ListOptions: gitlab.ListOptions{
+			Page:    1,
+			PerPage: namespacePerPage,
+		},
+		Search: gitlab.String(path[strings.LastIndex(path, "/")+1:]),
+	}
+	for {
+		namespaces, response, err := client.Namespaces.ListNamespaces(options)
+		if err != nil {
+			return nil, fmt.Errorf("Error searching for namespace %q: %s", path, err)
+		}
+		for _, namespace := range namespaces {
+			c.add(namespace)
+		}
+		if namespace, ok := c.paths[path]; ok {
+			return namespace, nil
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		options.Page = response.NextPage
+	}
+	return nil, fmt.Errorf("Invalid namespace path: %q", path)
+}
+
+// parseTwoPartID splits a composite "<first>:<second>" ID, as used to import
+// resources that live within a project or group.
+func parseTwoPartID(id string) (string, string, error) {
+	parts := strings.SplitN(id, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("Unexpected ID format (%q), expected <first>:<second>", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// doRequest issues a raw request against the gitlab API; it is used for those
+// endpoints that the go-gitlab client does not wrap (yet). The path is relative
+// to the configured base URL, and the response body (if any) is decoded into v.
+func doRequest(client *Client, method, path string, opt interface{}, v interface{}, options ...gitlab.OptionFunc) (*gitlab.Response, error) {
+	request, err := client.NewRequest(method, path, opt, options)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(request, v)
+}
+
+// pathEscape encodes a project or group ID (either numeric or in its
+// "namespace/path" form), or a file path, so that it can be used as a segment
+// of an API path: url.PathEscape encodes slashes as %2F, and spaces as %20
+// rather than +, which gitlab would take literally.
+func pathEscape(id string) string {
+	return url.PathEscape(id)
+}
+
+// transferGroupOptions represents the available options when transferring a
+// group under a different parent group.
+type transferGroupOptions struct {
+	GroupID *int `url:"group_id,omitempty" json:"group_id,omitempty"`
+}
+
+// transferGroup moves the given group (and all its subgroups and projects)
+// under a new parent group; a parent ID of 0 makes it a top-level group.
+func transferGroup(client *Client, gid string, parent int, options ...gitlab.OptionFunc) error {
+	opt := &transferGroupOptions{}
+	if parent != 0 {
+		opt.GroupID = gitlab.Int(parent)
+	}
+
+	log.Printf("[DEBUG] transfer gitlab group %s to parent %d", gid, parent)
+
+	_, err := doRequest(client, "POST", fmt.Sprintf("groups/%s/transfer", pathEscape(gid)), opt, nil, options...)
+	if err != nil {
+		return fmt.Errorf("Error transferring group %s to parent %d: %s", gid, parent, err)
+	}
+	return nil
+}
+
+// transferProjectOptions represents the available options when transferring
+// a project into a different namespace.
+type transferProjectOptions struct {
+	Namespace *int `url:"namespace,omitempty" json:"namespace,omitempty"`
+}
+
+// transferProject moves the given project into a new namespace, as checked by
+// checkNamespace; through API v4, which allows owners (rather than admins
+// alone) to transfer projects, it can be either a group or a user's, whereas
+// API v3 can only transfer projects into groups.
+func transferProject(client *Client, pid string, namespace int, options ...gitlab.OptionFunc) error {
+	kind, err := checkNamespace(client, namespace)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] transfer gitlab project %s to %s namespace %d", pid, kind, namespace)
+
+	switch {
+	case client.isV4():
+		opt := &transferProjectOptions{
+			Namespace: gitlab.Int(namespace),
 		}
+		_, err = doRequest(client, "PUT", fmt.Sprintf("projects/%s/transfer", pathEscape(pid)), opt, nil, options...)
+	case kind == "group":
+		_, err = doRequest(client, "POST", fmt.Sprintf("groups/%d/projects/%s", namespace, pathEscape(pid)), nil, nil, options...)
+	default:
+		return fmt.Errorf("Transferring project %s to %s namespace %d requires gitlab API v4", pid, kind, namespace)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Error transferring project %s to namespace %d: %s", pid, namespace, err)
+	}
+	return nil
+}
+
+// sudoOptions returns the request options needed to act on behalf of the user
+// set in the sudo argument of a resource, if any; otherwise the provider-level
+// sudo, if any, applies. Only groups and projects have a sudo argument: the
+// other resources are always managed as the provider-level sudo user.
+func sudoOptions(d *schema.ResourceData) []gitlab.OptionFunc {
+	if v, ok := d.GetOk("sudo"); ok {
+		return []gitlab.OptionFunc{gitlab.WithSudo(v.(string))}
 	}
-	return "", fmt.Errorf("Invalid namespace ID: %d", id)
+	return nil
 }
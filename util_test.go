@@ -112,6 +112,152 @@ func TestGitlab_validatePath(t *testing.T) {
 	}
 }
 
+func TestGitlab_validateDate(t *testing.T) {
+	cases := []struct {
+		String string
+		Errors int
+	}{
+		{
+			String: "2017-12-31",
+			Errors: 0,
+		},
+		{
+			String: "2017-13-01",
+			Errors: 1,
+		},
+		{
+			String: "31/12/2017",
+			Errors: 1,
+		},
+		{
+			String: "2017-12-31T00:00:00Z",
+			Errors: 1,
+		},
+	}
+	for _, tc := range cases {
+		_, errors := validateDate(tc.String, "expires_at")
+		if len(errors) != tc.Errors {
+			t.Fatalf("%s - got %d errors expected %d", tc.String, len(errors), tc.Errors)
+		}
+	}
+}
+
+func TestGitlab_sshKeyFingerprint(t *testing.T) {
+	cases := []struct {
+		Key         string
+		Fingerprint string
+		Errors      int
+	}{
+		{
+			Key:         testSSHKey,
+			Fingerprint: "6b:a8:d9:61:76:fb:f4:61:74:d4:4a:aa:05:be:e9:14",
+		},
+		{
+			Key:         "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAAAgQDmV/1Jdm3bV/y25ne0pl6l0WsLxMie7VBS5gECW2OzBgsoX+yPIaR/rqHvCF8w9oV2fvl+r9+7xiTtuD/GSYXYF2ACW1c9gxNqn0eEhR900ZcXUDBcvrW/DuDL+noJSKoY2HXtN8WyD3RcMNe7ivrZaZ+XFIkiFNAhWOBsd4wGKw==",
+			Fingerprint: "04:b1:14:a2:78:0e:93:3f:56:66:a5:a4:3a:b6:45:ec",
+		},
+		{
+			Key:    "AAAAC3NzaC1lZDI1NTE5AAAAIKxfVO9AJYHySh6ZkeY7hXYlGvaDf8g8DPot3raS47UZ",
+			Errors: 1,
+		},
+		{
+			Key:    "ssh-rsa AAAAC3NzaC1lZDI1NTE5AAAAIKxfVO9AJYHySh6ZkeY7hXYlGvaDf8g8DPot3raS47UZ",
+			Errors: 1,
+		},
+		{
+			Key:    "ssh-ed25519 not-base64!",
+			Errors: 1,
+		},
+		{
+			Key:    "ssh-ed25519 AAAA",
+			Errors: 1,
+		},
+	}
+	for _, tc := range cases {
+		_, errors := validateSSHKey(tc.Key, "key")
+		if len(errors) != tc.Errors {
+			t.Fatalf("%s - got %d errors expected %d", tc.Key, len(errors), tc.Errors)
+		}
+		if tc.Errors > 0 {
+			continue
+		}
+		if fingerprint, _ := sshKeyFingerprint(tc.Key); fingerprint != tc.Fingerprint {
+			t.Fatalf("%s - got fingerprint %s expected %s", tc.Key, fingerprint, tc.Fingerprint)
+		}
+	}
+}
+
+func TestGitlab_validateCron(t *testing.T) {
+	cases := []struct {
+		String string
+		Errors int
+	}{
+		{
+			String: "0 1 * * *",
+			Errors: 0,
+		},
+		{
+			String: "*/15 8-18 1,15 JAN-jun mon-FRI",
+			Errors: 0,
+		},
+		{
+			String: "30 2 * * 7",
+			Errors: 0,
+		},
+		{
+			String: "0 1 * *",
+			Errors: 1,
+		},
+		{
+			String: "60 24 0 13 8",
+			Errors: 5,
+		},
+		{
+			String: "*/0 5-1 * * SUNDAY",
+			Errors: 3,
+		},
+		{
+			String: "@daily",
+			Errors: 1,
+		},
+	}
+	for _, tc := range cases {
+		_, errors := validateCron(tc.String, "cron")
+		if len(errors) != tc.Errors {
+			t.Fatalf("%s - got %d errors expected %d", tc.String, len(errors), tc.Errors)
+		}
+	}
+}
+
+func TestGitlab_pathEscape(t *testing.T) {
+	cases := []struct {
+		String   string
+		Expected string
+	}{
+		{
+			String:   "42",
+			Expected: "42",
+		},
+		{
+			String:   "group/subgroup/project",
+			Expected: "group%2Fsubgroup%2Fproject",
+		},
+		{
+			String:   "docs/my file.md",
+			Expected: "docs%2Fmy%20file.md",
+		},
+		{
+			String:   "c++/a+b?.md",
+			Expected: "c++%2Fa+b%3F.md",
+		},
+	}
+	for _, tc := range cases {
+		if got := pathEscape(tc.String); got != tc.Expected {
+			t.Fatalf("%s - got %s expected %s", tc.String, got, tc.Expected)
+		}
+	}
+}
+
 func TestGitlab_visibilityHelpers(t *testing.T) {
 	cases := []struct {
 		String string
@@ -139,3 +285,40 @@ func TestGitlab_visibilityHelpers(t *testing.T) {
 		}
 	}
 }
+
+func TestGitlab_parseTwoPartID(t *testing.T) {
+	cases := []struct {
+		ID     string
+		First  string
+		Second string
+		Error  bool
+	}{
+		{
+			ID:     "42:7",
+			First:  "42",
+			Second: "7",
+		},
+		{
+			ID:     "team/app:7",
+			First:  "team/app",
+			Second: "7",
+		},
+		{
+			ID:    "42",
+			Error: true,
+		},
+		{
+			ID:    ":7",
+			Error: true,
+		},
+	}
+	for _, tc := range cases {
+		first, second, err := parseTwoPartID(tc.ID)
+		if (err != nil) != tc.Error {
+			t.Fatalf("%s - got error %v, expected error: %t", tc.ID, err, tc.Error)
+		}
+		if first != tc.First || second != tc.Second {
+			t.Fatalf("%s - got %q and %q expected %q and %q", tc.ID, first, second, tc.First, tc.Second)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// Project and group CI variables are managed through the same API, under
+// either projects/:id/variables or groups/:id/variables; go-gitlab only knows
+// about the keys and values of project variables, so the functions in this
+// file perform the requests themselves. The kind argument is either
+// "projects" or "groups". Variables are identified by their key and, since
+// the same key can be set for different environments, by their environment
+// scope.
+
+// the types of variables: environment variables, or files whose path is set in
+// an environment variable
+var variableTypes = []string{"env_var", "file"}
+
+var validVariableKey = regexp.MustCompile(`^[a-zA-Z0-9_]{1,255}$`)
+
+// A variable key can contain only letters, digits and '_', and is at most 255
+// characters long.
+func validateVariableKey(v interface{}, k string) (we []string, errors []error) {
+	value := v.(string)
+	if !validVariableKey.MatchString(value) {
+		errors = append(errors, fmt.Errorf("%q is an invalid %s: it can contain only letters, digits and '_', up to 255 characters", value, k))
+	}
+	return
+}
+
+// variable is a CI variable of a project or group.
+type variable struct {
+	Key              string `json:"key"`
+	Value            string `json:"value"`
+	VariableType     string `json:"variable_type"`
+	Protected        bool   `json:"protected"`
+	Masked           bool   `json:"masked"`
+	EnvironmentScope string `json:"environment_scope"`
+}
+
+// variableFilter selects the variable with a given environment scope, among
+// those with the same key.
+type variableFilter struct {
+	EnvironmentScope *string `url:"environment_scope,omitempty" json:"environment_scope,omitempty"`
+}
+
+// variableOptions represents the available options when creating or updating
+// a variable, or when retrieving or deleting it.
+type variableOptions struct {
+	Key              *string         `url:"key,omitempty" json:"key,omitempty"`
+	Value            *string         `url:"value,omitempty" json:"value,omitempty"`
+	VariableType     *string         `url:"variable_type,omitempty" json:"variable_type,omitempty"`
+	Protected        *bool           `url:"protected,omitempty" json:"protected,omitempty"`
+	Masked           *bool           `url:"masked,omitempty" json:"masked,omitempty"`
+	EnvironmentScope *string         `url:"environment_scope,omitempty" json:"environment_scope,omitempty"`
+	Filter           *variableFilter `url:"filter,omitempty" json:"filter,omitempty"`
+}
+
+// scopeFilter returns the options selecting the variable with the given
+// environment scope.
+func scopeFilter(scope string) *variableOptions {
+	return &variableOptions{Filter: &variableFilter{EnvironmentScope: gitlab.String(scope)}}
+}
+
+func getVariable(client *Client, kind, id, key, scope string, options ...gitlab.OptionFunc) (*variable, *gitlab.Response, error) {
+	if !client.isV4() {
+		return nil, nil, fmt.Errorf("Variables require gitlab API v4")
+	}
+
+	v := &variable{}
+	response, err := doRequest(client, "GET", fmt.Sprintf("%s/%s/variables/%s", kind, pathEscape(id), pathEscape(key)), scopeFilter(scope), v, options...)
+	if err != nil {
+		return nil, response, err
+	}
+	return v, response, nil
+}
+
+// listVariables returns all the variables of a project or group, going
+// through all the pages of the list.
+func listVariables(client *Client, kind, id string, options ...gitlab.OptionFunc) ([]*variable, *gitlab.Response, error) {
+	if !client.isV4() {
+		return nil, nil, fmt.Errorf("Variables require gitlab API v4")
+	}
+
+	variables := []*variable{}
+	opt := &gitlab.ListOptions{PerPage: 100, Page: 1}
+	for {
+		page := []*variable{}
+		response, err := doRequest(client, "GET", fmt.Sprintf("%s/%s/variables", kind, pathEscape(id)), opt, &page, options...)
+		if err != nil {
+			return nil, response, err
+		}
+		variables = append(variables, page...)
+		if response.NextPage == 0 {
+			return variables, response, nil
+		}
+		opt.Page = response.NextPage
+	}
+}
+
+func createVariable(client *Client, kind, id string, opt *variableOptions, options ...gitlab.OptionFunc) error {
+	if !client.isV4() {
+		return fmt.Errorf("Variables require gitlab API v4")
+	}
+
+	log.Printf("[DEBUG] create variable %s (%s) of gitlab %s %s", *opt.Key, *opt.EnvironmentScope, kind, id)
+
+	_, err := doRequest(client, "POST", fmt.Sprintf("%s/%s/variables", kind, pathEscape(id)), opt, nil, options...)
+	return err
+}
+
+// updateVariable updates the variable with the given key and environment
+// scope.
+func updateVariable(client *Client, kind, id, key, scope string, opt *variableOptions, options ...gitlab.OptionFunc) error {
+	if !client.isV4() {
+		return fmt.Errorf("Variables require gitlab API v4")
+	}
+
+	log.Printf("[DEBUG] update variable %s (%s) of gitlab %s %s", key, scope, kind, id)
+
+	opt.Filter = scopeFilter(scope).Filter
+	_, err := doRequest(client, "PUT", fmt.Sprintf("%s/%s/variables/%s", kind, pathEscape(id), pathEscape(key)), opt, nil, options...)
+	return err
+}
+
+func deleteVariable(client *Client, kind, id, key, scope string, options ...gitlab.OptionFunc) error {
+	log.Printf("[DEBUG] delete variable %s (%s) of gitlab %s %s", key, scope, kind, id)
+
+	_, err := doRequest(client, "DELETE", fmt.Sprintf("%s/%s/variables/%s", kind, pathEscape(id), pathEscape(key)), scopeFilter(scope), nil, options...)
+	return err
+}
+
+// variableSchema returns the schema of the settings of a variable, shared by
+// project and group variables.
+func variableSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"key": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validateVariableKey,
+		},
+		"value": {
+			Type:      schema.TypeString,
+			Required:  true,
+			Sensitive: true,
+		},
+		"variable_type": {
+			Type:         schema.TypeString,
+			Description:  "Either env_var, for an environment variable, or file, for a file whose path is set in the environment variable.",
+			Optional:     true,
+			Default:      "env_var",
+			ValidateFunc: validateValueFunc(variableTypes),
+		},
+		"protected": {
+			Type:        schema.TypeBool,
+			Description: "Whether the variable is only set in pipelines running on protected branches and tags.",
+			Optional:    true,
+			Default:     false,
+		},
+		"masked": {
+			Type:        schema.TypeBool,
+			Description: "Whether the value is masked in job logs; masked values must be at least 8 characters long, on a single line.",
+			Optional:    true,
+			Default:     false,
+		},
+		"environment_scope": {
+			Type:        schema.TypeString,
+			Description: "The environments the variable is set for (e.g. production or review/*); by default, all of them.",
+			Optional:    true,
+			Default:     "*",
+			ForceNew:    true,
+		},
+	}
+}
+
+// expandVariable returns the options to create or update the variable with
+// the given settings.
+func expandVariable(m map[string]interface{}) *variableOptions {
+	return &variableOptions{
+		Key:              gitlab.String(m["key"].(string)),
+		Value:            gitlab.String(m["value"].(string)),
+		VariableType:     gitlab.String(m["variable_type"].(string)),
+		Protected:        gitlab.Bool(m["protected"].(bool)),
+		Masked:           gitlab.Bool(m["masked"].(bool)),
+		EnvironmentScope: gitlab.String(m["environment_scope"].(string)),
+	}
+}
+
+// variableSettings returns the settings of the variable of the given
+// resource.
+func variableSettings(d *schema.ResourceData) map[string]interface{} {
+	m := map[string]interface{}{}
+	for k := range variableSchema() {
+		m[k] = d.Get(k)
+	}
+	return m
+}
+
+// parseVariableID parses the ID of a project or group variable, made of the
+// project or group, the key and the environment scope.
+func parseVariableID(id string) (string, string, string, error) {
+	parts := strings.SplitN(id, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("Unexpected ID format (%q), expected <owner>:<key>:<environment scope>", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// readVariable sets the settings of the variable of the given resource from
+// gitlab, or removes it from the state if it no longer exists.
+func readVariable(d *schema.ResourceData, client *Client, kind, id string) error {
+	key := d.Get("key").(string)
+	scope := d.Get("environment_scope").(string)
+	log.Printf("[DEBUG] read gitlab variable %s", d.Id())
+
+	v, response, err := getVariable(client, kind, id, key, scope)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			log.Printf("[WARN] removing variable %s from state because it no longer exists in gitlab", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("value", v.Value)
+	d.Set("variable_type", v.VariableType)
+	d.Set("protected", v.Protected)
+	d.Set("masked", v.Masked)
+	d.Set("environment_scope", v.EnvironmentScope)
+	return nil
+}
+
+// declaredVariables returns the values of the variables in the configuration,
+// by key.
+func declaredVariables(d *schema.ResourceData) map[string]string {
+	variables := map[string]string{}
+	for k, v := range d.Get("variables").(map[string]interface{}) {
+		variables[k] = v.(string)
+	}
+	return variables
+}
+
+// setVariablesToState sets the actual variables of a project with the
+// environment scope of the given resource into the state; unless undeclared
+// variables are ignored, those added outside of Terraform show up there, so
+// that the next plan removes them.
+func setVariablesToState(d *schema.ResourceData, variables []*variable) error {
+	declared := declaredVariables(d)
+	scope := d.Get("environment_scope").(string)
+	values := map[string]interface{}{}
+	for _, v := range variables {
+		if v.EnvironmentScope != scope {
+			continue
+		}
+		if _, ok := declared[v.Key]; !ok && d.Get("ignore_undeclared").(bool) {
+			continue
+		}
+		values[v.Key] = v.Value
+	}
+	return d.Set("variables", values)
+}
+
+// applyVariables makes the variables of a project with the environment scope
+// of the given resource match the declared ones: missing variables are
+// created, those whose value differs are updated and the undeclared ones are
+// deleted, except, when they are ignored, those that were never declared;
+// variables that are already as declared are left untouched.
+func applyVariables(client *Client, kind, id string, d *schema.ResourceData) error {
+	scope := d.Get("environment_scope").(string)
+	variables, _, err := listVariables(client, kind, id)
+	if err != nil {
+		return err
+	}
+	actual := map[string]*variable{}
+	for _, v := range variables {
+		if v.EnvironmentScope == scope {
+			actual[v.Key] = v
+		}
+	}
+
+	declared := declaredVariables(d)
+	for key, value := range declared {
+		got, ok := actual[key]
+		switch {
+		case !ok:
+			options := &variableOptions{
+				Key:              gitlab.String(key),
+				Value:            gitlab.String(value),
+				EnvironmentScope: gitlab.String(scope),
+			}
+			if err := createVariable(client, kind, id, options); err != nil {
+				return fmt.Errorf("Error creating variable %s of %s %s: %s", key, kind, id, err)
+			}
+		case got.Value != value:
+			options := &variableOptions{Value: gitlab.String(value)}
+			if err := updateVariable(client, kind, id, key, scope, options); err != nil {
+				return fmt.Errorf("Error updating variable %s of %s %s: %s", key, kind, id, err)
+			}
+		}
+	}
+
+	old, _ := d.GetChange("variables")
+	for key := range actual {
+		if _, ok := declared[key]; ok {
+			continue
+		}
+		if _, ok := old.(map[string]interface{})[key]; !ok && d.Get("ignore_undeclared").(bool) {
+			continue
+		}
+		if err := deleteVariable(client, kind, id, key, scope); err != nil {
+			return fmt.Errorf("Error deleting variable %s of %s %s: %s", key, kind, id, err)
+		}
+	}
+	return nil
+}
+
+// deleteVariables deletes the variables of a project that are managed by
+// Terraform, leaving the undeclared ones alone.
+func deleteVariables(client *Client, kind, id string, d *schema.ResourceData) error {
+	scope := d.Get("environment_scope").(string)
+	for key := range declaredVariables(d) {
+		if err := deleteVariable(client, kind, id, key, scope); err != nil {
+			return fmt.Errorf("Error deleting variable %s of %s %s: %s", key, kind, id, err)
+		}
+	}
+	return nil
+}